@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConnectionFactorySpec defines a source type that Virtual Databases of the namespace can use in addition to
+// the ones the operator ships with
+// +k8s:openapi-gen=true
+type ConnectionFactorySpec struct {
+	// Name of the source type as used in the FOREIGN DATA WRAPPER and the type of the Data Sources, defaults
+	// to the name of the resource
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Name"
+	Name string `json:"name,omitempty"`
+	// Class names of the JDBC drivers
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Driver Names"
+	DriverNames []string `json:"driverNames,omitempty"`
+	// Name of the translator
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Translator Name"
+	TranslatorName string `json:"translatorName,omitempty"`
+	// Hibernate dialect of the source
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Dialect"
+	Dialect string `json:"dialect,omitempty"`
+	// Prefix of the Spring Boot properties the Data Source properties are mapped to, defaults to
+	// spring.teiid.data.<name>
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Spring Boot Property Prefix"
+	SpringBootPropertyPrefix string `json:"springBootPropertyPrefix,omitempty"`
+	// Source is accessed through JDBC
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="JDBC"
+	JdbcSource bool `json:"jdbc,omitempty"`
+	// Maven artifacts of the driver and translator as groupId:artifactId:version, added to the build of the
+	// Virtual Databases using the source type
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Dependencies"
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConnectionFactory is the Schema for the connectionfactories API
+// +k8s:openapi-gen=true
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Connection Factory"
+// +kubebuilder:resource:path=connectionfactories,shortName=cf;cfs
+// +kubebuilder:singular=connectionfactory
+type ConnectionFactory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Connection Factory specification
+	Spec ConnectionFactorySpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ConnectionFactoryList contains a list of ConnectionFactory
+type ConnectionFactoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ConnectionFactory `json:"items"`
+}
+
+const (
+	// ConnectionFactoryKind --
+	ConnectionFactoryKind string = "ConnectionFactory"
+)
+
+func init() {
+	SchemeBuilder.Register(&ConnectionFactory{}, &ConnectionFactoryList{})
+}
@@ -2,8 +2,10 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -36,10 +38,76 @@ type VirtualDatabaseSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Datasources Configuration"
 	DataSources []DataSourceObject `json:"datasources,omitempty"`
-	// Defines the services (LoadBalancer, NodePort, 3scale) to expose
+	// Defines the services (LoadBalancer, NodePort, Route, Ingress, Gateway, 3scale) to expose
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Services Created"
 	Expose []ExposeType `json:"expose,omitempty"`
+	// Host, TLS and other options of the Route, Ingress or Gateway routes that expose the Virtual Database
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Expose Options"
+	ExposeOptions *ExposeOptionsObject `json:"exposeOptions,omitempty"`
+	// Security configuration for the Virtual Database
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Security"
+	Security *SecurityObject `json:"security,omitempty"`
+	// NetworkPolicy restricts who can reach the Virtual Database, no NetworkPolicy is generated when omitted
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Network Policy"
+	NetworkPolicy *NetworkPolicyObject `json:"networkPolicy,omitempty"`
+	// Protocols the Virtual Database listens on, JDBC and PostgreSQL are served with and without TLS and OData
+	// without TLS when omitted
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Protocols"
+	Protocols *ProtocolsObject `json:"protocols,omitempty"`
+	// Autoscaling of the Virtual Database, when configured replicas is managed by the HorizontalPodAutoscaler
+	// through the scale subresource
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Autoscaling"
+	Autoscaling *AutoscalingObject `json:"autoscaling,omitempty"`
+	// PodDisruptionBudget generated when there is more than one replica, maxUnavailable of 1 when omitted
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Disruption Budget"
+	DisruptionBudget *DisruptionBudgetObject `json:"disruptionBudget,omitempty"`
+	// Scheduling constraints of the pods
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Affinity"
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations of the pods
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Labels of the nodes the pods can be scheduled on
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Node Selector"
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// How the pods are spread across zones, nodes and other topology domains
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Topology Spread Constraints"
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClass of the pods
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Priority Class Name"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Additions to the pods of the Virtual Database, the settings managed by the operator take precedence
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Pod Template"
+	PodTemplate *PodTemplateObject `json:"podTemplate,omitempty"`
+	// Liveness, readiness and startup probes of the Virtual Database container
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Probes"
+	Probes *ProbesObject `json:"probes,omitempty"`
+	// Volume the buffer manager spills large intermediate results to, the container scratch space when omitted
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Buffer Storage"
+	BufferStorage *BufferStorageObject `json:"bufferStorage,omitempty"`
+	// How a new version of the Virtual Database replaces the running one
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Rollout"
+	Rollout *RolloutObject `json:"rollout,omitempty"`
+	// Version from status.history to redeploy without rebuilding, cleared once the rollback started
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Rollback To"
+	RollbackTo string `json:"rollbackTo,omitempty"`
 }
 
 // VirtualDatabaseStatus defines the observed state of VirtualDatabase
@@ -71,6 +139,11 @@ type VirtualDatabaseStatus struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Route Exposed for OData"
 	Route string `json:"route,omitempty"`
 
+	// Endpoints through which the protocols of the vdb are reachable by the clients
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Endpoints"
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+
 	// Deployed vdb version.
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Version Of the VDB deployed"
@@ -80,8 +153,141 @@ type VirtualDatabaseStatus struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="CacheStore In use"
 	CacheStore string `json:"cachestore,omitempty"`
+
+	// Number of pods of the Virtual Database
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Replicas"
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Number of pods of the Virtual Database that are ready
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Ready Replicas"
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Label selector of the pods, used by the scale subresource
+	Selector string `json:"selector,omitempty"`
+
+	// Version the clients are routed to with the BlueGreen rollout strategy
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Active Version"
+	ActiveVersion string `json:"activeVersion,omitempty"`
+
+	// Version replaced by the active version, kept for a rollback until PreviousVersionExpiry. Empty for the
+	// Deployment of the Rolling strategy
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// Time the Deployment of the previous version is removed
+	PreviousVersionExpiry *metav1.Time `json:"previousVersionExpiry,omitempty"`
+
+	// Time the verification of the deploying version first failed, it is retried until the verification timeout
+	VerificationFailed *metav1.Time `json:"verificationFailed,omitempty"`
+
+	// Progress of the Canary rollout of the new version
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Canary"
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// Successful builds of the Virtual Database, most recent last, that can be rolled back to
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="History"
+	History []BuildHistoryEntry `json:"history,omitempty"`
+
+	// Drivers and translators resolved for the Data Sources
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Data Sources"
+	DataSources []DataSourceStatus `json:"dataSources,omitempty"`
 }
 
+// DataSourceStatus - artifacts resolved for a Data Source
+// +k8s:openapi-gen=true
+type DataSourceStatus struct {
+	// Name of the Data Source
+	Name string `json:"name"`
+	// Driver artifact as groupId:artifactId:version
+	Driver string `json:"driver,omitempty"`
+	// Name of the custom translator
+	Translator string `json:"translator,omitempty"`
+	// Translator artifact as groupId:artifactId:version
+	TranslatorArtifact string `json:"translatorArtifact,omitempty"`
+	// The artifacts were found in the maven repositories of the build, false when a repository could not be
+	// reached and the build decides
+	Resolved bool `json:"resolved,omitempty"`
+}
+
+// BuildHistoryEntry - image built for a version of the Virtual Database
+// +k8s:openapi-gen=true
+type BuildHistoryEntry struct {
+	// Version of the Virtual Database
+	Version string `json:"version"`
+	// Digest of the Virtual Database the image was built from
+	Digest string `json:"digest,omitempty"`
+	// Image the build pushed to the ImageStream tag, referenced by its digest
+	Image string `json:"image"`
+	// Time the build completed
+	Built metav1.Time `json:"built,omitempty"`
+	// Software bill of materials of the image
+	SBOM *SBOMStatus `json:"sbom,omitempty"`
+	// Why the image has no software bill of materials
+	SBOMUnavailable string `json:"sbomUnavailable,omitempty"`
+}
+
+// SBOMStatus - CycloneDX software bill of materials of an image and the versions resolved by its build
+// +k8s:openapi-gen=true
+type SBOMStatus struct {
+	// ConfigMap holding the gzipped CycloneDX JSON document under the key bom.json.gz
+	ConfigMap string `json:"configMap"`
+	// Number of components in the image
+	Components int32 `json:"components,omitempty"`
+	// Resolved version of teiid-spring-boot
+	TeiidSpringBootVersion string `json:"teiidSpringBootVersion,omitempty"`
+	// Resolved version of Spring Boot
+	SpringBootVersion string `json:"springBootVersion,omitempty"`
+	// Drivers of the data sources as groupId:artifactId:version
+	Drivers []string `json:"drivers,omitempty"`
+}
+
+// CanaryStatus - step of the Canary rollout
+// +k8s:openapi-gen=true
+type CanaryStatus struct {
+	// Version receiving the canary traffic
+	Version string `json:"version"`
+	// Index of the current step
+	Step int32 `json:"step"`
+	// Percentage of the OData requests sent to the version
+	Weight int32 `json:"weight"`
+	// Time the current step started
+	StepStarted metav1.Time `json:"stepStarted,omitempty"`
+}
+
+// EndpointStatus - endpoint through which a protocol of the Virtual Database is reachable
+// +k8s:openapi-gen=true
+type EndpointStatus struct {
+	// Protocol of the endpoint
+	Protocol EndpointProtocol `json:"protocol"`
+	// URL to reach the endpoint from inside of the cluster
+	InternalURL string `json:"internalUrl,omitempty"`
+	// URL to reach the endpoint from outside of the cluster
+	ExternalURL string `json:"externalUrl,omitempty"`
+	// Whether the protocol is secured with TLS
+	TLS bool `json:"tls,omitempty"`
+}
+
+// EndpointProtocol - protocol served by the Virtual Database
+type EndpointProtocol string
+
+const (
+	// ODataProtocol OData REST API
+	ODataProtocol EndpointProtocol = "odata"
+	// OpenAPIProtocol REST API defined by the OpenAPI document
+	OpenAPIProtocol EndpointProtocol = "openapi"
+	// JDBCProtocol Teiid JDBC
+	JDBCProtocol EndpointProtocol = "jdbc"
+	// PostgresProtocol PostgreSQL wire protocol (ODBC)
+	PostgresProtocol EndpointProtocol = "postgres"
+	// MetricsProtocol Prometheus metrics
+	MetricsProtocol EndpointProtocol = "metrics"
+)
+
 // OpenShiftObject ...
 type OpenShiftObject interface {
 	metav1.Object
@@ -99,6 +305,26 @@ type VirtualDatabaseBuildObject struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="S2I based Source information"
 	Source Source `json:"source,omitempty"`
+	// Maven arguments, profiles and properties of the build, merged with the defaults of the operator
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Maven Build"
+	Maven *MavenBuildObject `json:"maven,omitempty"`
+}
+
+// MavenBuildObject - customizations of the Maven build of the service image
+// +k8s:openapi-gen=true
+type MavenBuildObject struct {
+	// Arguments appended to the Maven command line
+	Args []string `json:"args,omitempty"`
+	// Profiles activated in the build
+	Profiles []string `json:"profiles,omitempty"`
+	// System properties of the build, these take precedence over the defaults of the operator
+	Properties map[string]string `json:"properties,omitempty"`
+	// Java version the sources are compiled for, 1.8 when omitted
+	JDK string `json:"jdk,omitempty"`
+	// S2I image the service image is built with instead of the builder image of the operator, for example one
+	// with a newer JDK. Its Maven repository is not pre-populated
+	BuilderImage string `json:"builderImage,omitempty"`
 }
 
 // Source VDB coordinates to locate the source code to build
@@ -144,6 +370,296 @@ type ValueSource struct {
 	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
+// PodTemplateObject - volumes, containers and settings added to the generated pods
+// +k8s:openapi-gen=true
+type PodTemplateObject struct {
+	// Labels added to the pods
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to the pods
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Volumes added to the pods
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// Volumes mounted into the Virtual Database container
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Init containers run before the Virtual Database container
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+	// Containers run next to the Virtual Database container
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// Security context of the pods
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// Security context of the Virtual Database container
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// Service account the pods run as
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Secrets used to pull the images of the sidecars and init containers
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+}
+
+// RolloutObject - strategy used to replace the running version of the Virtual Database
+// +k8s:openapi-gen=true
+type RolloutObject struct {
+	// Rolling (default) updates the Deployment in place, BlueGreen deploys every version as its own Deployment
+	// and switches the clients over once it is ready, Canary shifts the OData traffic to the new version in steps
+	// +kubebuilder:validation:Enum=Rolling;BlueGreen;Canary
+	Strategy RolloutStrategy `json:"strategy,omitempty"`
+	// Options of the BlueGreen strategy, the retention period also applies to the Canary strategy
+	BlueGreen *BlueGreenObject `json:"blueGreen,omitempty"`
+	// Options of the Canary strategy
+	Canary *CanaryObject `json:"canary,omitempty"`
+}
+
+// RolloutStrategy - strategy of the rollout
+type RolloutStrategy string
+
+const (
+	// RolloutRolling rolling update of the Deployment
+	RolloutRolling RolloutStrategy = "Rolling"
+	// RolloutBlueGreen Deployment per version, the Service is switched once the new version is verified
+	RolloutBlueGreen RolloutStrategy = "BlueGreen"
+	// RolloutCanary Deployment per version, the OData traffic is shifted to the new version in steps
+	RolloutCanary RolloutStrategy = "Canary"
+)
+
+// CanaryObject - steps and analysis of the Canary strategy. The OData traffic of the Route or Gateway is split
+// between the Services of the versions, JDBC and PostgreSQL stay on the active version until the promotion
+// +k8s:openapi-gen=true
+type CanaryObject struct {
+	// Share of the traffic sent to the new version per step, the new version is promoted after the last step.
+	// Defaults to 10% then 50%, each for 5 minutes
+	Steps []CanaryStep `json:"steps,omitempty"`
+	// Error rate analysis of the new version, the canary is aborted when the error rate is exceeded. The canary
+	// is also aborted when a pod of the new version is not ready or restarts
+	Analysis *CanaryAnalysisObject `json:"analysis,omitempty"`
+}
+
+// CanaryStep - share of the traffic sent to the new version and for how long
+// +k8s:openapi-gen=true
+type CanaryStep struct {
+	// Percentage of the OData requests sent to the new version
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+	// How long the weight is kept before moving to the next step, ex: 10m. Defaults to 5m
+	Pause string `json:"pause,omitempty"`
+}
+
+// CanaryAnalysisObject - Prometheus query giving the error rate of the new version
+// +k8s:openapi-gen=true
+type CanaryAnalysisObject struct {
+	// URL of the Prometheus API, ex: https://thanos-querier.openshift-monitoring.svc:9091
+	PrometheusURL string `json:"prometheusUrl"`
+	// PromQL query returning the error rate of the new version, {{namespace}}, {{service}} and {{version}} are
+	// replaced. Defaults to the share of the requests processed by the new version that failed with a Teiid buffer
+	// out of disk error. The step is held while the query returns no samples
+	Query string `json:"query,omitempty"`
+	// Highest acceptable error rate, ex: 0.01. Defaults to 0.05
+	MaxErrorRate string `json:"maxErrorRate,omitempty"`
+}
+
+// BlueGreenObject - verification of the new version and retention of the previous one
+// +k8s:openapi-gen=true
+type BlueGreenObject struct {
+	// OData path queried on a pod of the new version before switching, ex: /odata/portfolio/CustomerZip?$top=1.
+	// The query must succeed without authentication. Only readiness is checked when omitted
+	VerificationPath string `json:"verificationPath,omitempty"`
+	// How long the previous version is kept after the switch, for an instant rollback. Defaults to 1h
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
+}
+
+// BufferStorageObject - volume of the buffer manager, the disk usage of the buffer manager is bounded by its size
+// +k8s:openapi-gen=true
+type BufferStorageObject struct {
+	// Ephemeral (default) for an emptyDir, PersistentVolumeClaim for one claim shared by the replicas or PerReplica
+	// for a claim per replica, which deploys the Virtual Database as a StatefulSet
+	// +kubebuilder:validation:Enum=Ephemeral;PersistentVolumeClaim;PerReplica
+	Type BufferStorageType `json:"type,omitempty"`
+	// Size of the volume, ex: 10Gi
+	Size string `json:"size"`
+	// StorageClass of the claims, the default storage class when omitted
+	StorageClass string `json:"storageClass,omitempty"`
+	// AccessMode of the shared claim, defaults to ReadWriteOnce. Replicas spread across nodes need ReadWriteMany
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+}
+
+// BufferStorageType - kind of volume used by the buffer manager
+type BufferStorageType string
+
+const (
+	// BufferStorageEphemeral emptyDir volume limited to the size
+	BufferStorageEphemeral BufferStorageType = "Ephemeral"
+	// BufferStoragePersistentVolumeClaim one claim shared by the replicas, each replica uses its own directory
+	BufferStoragePersistentVolumeClaim BufferStorageType = "PersistentVolumeClaim"
+	// BufferStoragePerReplica claim per replica created by a StatefulSet
+	BufferStoragePerReplica BufferStorageType = "PerReplica"
+)
+
+// ProbesObject - overrides of the probes of the Virtual Database container, a probe without a handler
+// keeps the default health check and only changes the timings
+// +k8s:openapi-gen=true
+type ProbesObject struct {
+	// Liveness probe, defaults to the liveness health group of the actuator
+	Liveness *corev1.Probe `json:"liveness,omitempty"`
+	// Readiness probe, defaults to the readiness health group of the actuator
+	Readiness *corev1.Probe `json:"readiness,omitempty"`
+	// Startup probe, defaults to the liveness health group allowing ten minutes to start
+	Startup *corev1.Probe `json:"startup,omitempty"`
+}
+
+// DisruptionBudgetObject - the pods that must stay available during voluntary disruptions, only one of
+// minAvailable or maxUnavailable can be given
+// +k8s:openapi-gen=true
+type DisruptionBudgetObject struct {
+	// Number or percentage of pods that must remain available
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Number or percentage of pods that can be unavailable
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingObject - bounds and targets of the HorizontalPodAutoscaler
+// +k8s:openapi-gen=true
+type AutoscalingObject struct {
+	// Lower limit of replicas, defaults to 1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// Upper limit of replicas
+	MaxReplicas int32 `json:"maxReplicas"`
+	// Target average CPU utilization as percentage of the requested CPU, 80 when no target is given
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+	// Target average memory utilization as percentage of the requested memory
+	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+	// Custom metrics of the pods, such as org_teiid_WaitingRequestsCount or org_teiid_SessionCount scraped from
+	// the JMX exporter, require an adapter that serves them through the custom metrics API
+	Metrics []AutoscalingMetric `json:"metrics,omitempty"`
+}
+
+// AutoscalingMetric - custom metric of the pods and the target average value
+// +k8s:openapi-gen=true
+type AutoscalingMetric struct {
+	// Name of the metric
+	Name string `json:"name"`
+	// Target average value of the metric across the pods, a quantity such as 10 or 500m
+	TargetAverageValue string `json:"targetAverageValue"`
+}
+
+// ProtocolsObject - the listeners of the Virtual Database
+// +k8s:openapi-gen=true
+type ProtocolsObject struct {
+	// Teiid JDBC, secure on port 31443 and insecure on port 31000, both enabled when omitted
+	JDBC *ProtocolObject `json:"jdbc,omitempty"`
+	// PostgreSQL, secure on port 5433 and insecure on port 5432, both enabled when omitted
+	PG *ProtocolObject `json:"pg,omitempty"`
+	// OData, secure https on port 8443 or insecure http on port 8080, only one of them can be enabled. When
+	// disabled port 8080 still serves the health checks, but is not exposed
+	OData *ProtocolObject `json:"odata,omitempty"`
+}
+
+// ProtocolObject - the secure and insecure listeners of a protocol
+// +k8s:openapi-gen=true
+type ProtocolObject struct {
+	// Secure listener using TLS
+	Secure *bool `json:"secure,omitempty"`
+	// Insecure plain text listener
+	Insecure *bool `json:"insecure,omitempty"`
+}
+
+// NetworkPolicyObject - peers allowed to reach the ports of the Virtual Database
+// +k8s:openapi-gen=true
+type NetworkPolicyObject struct {
+	// Peers allowed to reach the OData, JDBC and PostgreSQL ports, only the pods in the same namespace when
+	// omitted
+	From []networkingv1.NetworkPolicyPeer `json:"from,omitempty"`
+	// Peers of the routers, Ingress and Gateway controllers allowed to reach the exposed ports. When omitted the
+	// namespaces labelled network.openshift.io/policy-group=ingress for Routes and all the namespaces for Ingress
+	// and Gateway, whose controllers carry no common label
+	IngressControllers []networkingv1.NetworkPolicyPeer `json:"ingressControllers,omitempty"`
+	// Peers allowed to scrape the metrics, the namespaces labelled network.openshift.io/policy-group=monitoring
+	// when omitted
+	Metrics []networkingv1.NetworkPolicyPeer `json:"metrics,omitempty"`
+	// Peers allowed to reach the jolokia agent, none when omitted
+	Jolokia []networkingv1.NetworkPolicyPeer `json:"jolokia,omitempty"`
+	// Egress restricts the outgoing traffic to DNS, the pods in the same namespace, the hosts of the jdbc-url
+	// of the data sources and the OIDC issuer. Hosts outside of the cluster are resolved to IP addresses when the
+	// spec changes, hosts whose addresses change must be allowed through additionalEgress
+	Egress bool `json:"egress,omitempty"`
+	// Additional egress rules, when egress is restricted
+	AdditionalEgress []networkingv1.NetworkPolicyEgressRule `json:"additionalEgress,omitempty"`
+}
+
+// SecurityObject - defines how the clients of the Virtual Database are authenticated and authorized
+// +k8s:openapi-gen=true
+type SecurityObject struct {
+	// OpenID Connect (Keycloak) based authentication
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="OpenID Connect"
+	OIDC *OIDCObject `json:"oidc,omitempty"`
+	// Data roles of the Virtual Database, mapped from the identity provider groups
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Data Roles"
+	Roles []DataRoleObject `json:"roles,omitempty"`
+}
+
+// DataRoleObject - Teiid data role and the permissions granted to it
+// +k8s:openapi-gen=true
+type DataRoleObject struct {
+	// Name of the data role
+	Name string `json:"name"`
+	// Identity provider groups (roles) that are mapped to this data role
+	Groups []string `json:"groups,omitempty"`
+	// Assign this data role to any authenticated user
+	AnyAuthenticated bool `json:"anyAuthenticated,omitempty"`
+	// Permissions granted to the data role
+	Permissions []PermissionObject `json:"permissions,omitempty"`
+}
+
+// PermissionObject - permission on a schema or a view
+// +k8s:openapi-gen=true
+type PermissionObject struct {
+	// Name of the schema
+	Schema string `json:"schema"`
+	// Name of the view or table in the schema, when omitted the permission applies to whole schema
+	View string `json:"view,omitempty"`
+	// Privileges granted, one of SELECT, INSERT, UPDATE, DELETE, EXECUTE, ALTER, DROP or ALL PRIVILEGES. Defaults to SELECT
+	Privileges []string `json:"privileges,omitempty"`
+}
+
+// OIDCObject - OpenID Connect identity provider configuration
+// +k8s:openapi-gen=true
+type OIDCObject struct {
+	// Issuer URL of the identity provider, either the Keycloak auth server URL (https://host/auth) or the
+	// realm issuer URL (https://host/auth/realms/myrealm)
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Issuer URL"
+	IssuerURL string `json:"issuerUrl"`
+	// Realm name, optional when it is part of the issuer URL
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Realm"
+	Realm string `json:"realm,omitempty"`
+	// Client ID registered with the identity provider
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Client ID"
+	ClientID string `json:"clientId"`
+	// Secret key that holds the client secret, not needed for public clients
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Client Credentials"
+	CredentialsSecretRef *corev1.SecretKeySelector `json:"credentialsSecretRef,omitempty"`
+	// Secret key that holds the PEM encoded CA certificate(s) of the identity provider, added to the truststore
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="CA Certificate"
+	CACertificateSecretRef *corev1.SecretKeySelector `json:"caCertificateSecretRef,omitempty"`
+	// Defines how the roles in the token are mapped to the user
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Role Mapping"
+	RoleMapping *OIDCRoleMapping `json:"roleMapping,omitempty"`
+}
+
+// OIDCRoleMapping - defines how the user principal and roles are read from the token
+// +k8s:openapi-gen=true
+type OIDCRoleMapping struct {
+	// Use the client (resource) roles of the token instead of the realm roles
+	UseResourceRoles bool `json:"useResourceRoles,omitempty"`
+	// Token claim used as the user name, ex: preferred_username. Defaults to sub
+	PrincipalAttribute string `json:"principalAttribute,omitempty"`
+}
+
 // ExposeType - type of service to be exposed
 type ExposeType string
 
@@ -156,8 +672,43 @@ const (
 	Route ExposeType = "Route"
 	// ExposeVia3scale just service, not route
 	ExposeVia3scale ExposeType = "ExposeVia3scale"
+	// Ingress Kubernetes Ingress to expose
+	Ingress ExposeType = "Ingress"
+	// Gateway Kubernetes Gateway API HTTPRoute and TLSRoute to expose
+	Gateway ExposeType = "Gateway"
+	// PassthroughRoute Openshift Route with TLS passthrough to the secure JDBC port. The routing is based on SNI,
+	// which PostgreSQL clients do not send, use a LoadBalancer or NodePort for PostgreSQL
+	PassthroughRoute ExposeType = "PassthroughRoute"
 )
 
+// ExposeOptionsObject - options of the Route, Ingress or Gateway routes
+// +k8s:openapi-gen=true
+type ExposeOptionsObject struct {
+	// Host name to expose the Virtual Database on, generated by the cluster when omitted
+	Host string `json:"host,omitempty"`
+	// Class of the Ingress controller to use
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Annotations added to the Route, Ingress or Gateway routes
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Name of the Secret with the TLS certificate (tls.crt, tls.key) for the Ingress
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// Gateway to attach the HTTPRoute and TLSRoutes to, required for Gateway
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// GatewayReference - reference to a Gateway API Gateway
+// +k8s:openapi-gen=true
+type GatewayReference struct {
+	// Name of the Gateway
+	Name string `json:"name"`
+	// Namespace of the Gateway, defaults to the namespace of the Virtual Database
+	Namespace string `json:"namespace,omitempty"`
+	// Listener of the Gateway used by the HTTPRoute
+	SectionName string `json:"sectionName,omitempty"`
+	// Listener of the Gateway, with TLS passthrough mode, used by the TLSRoute of the secure JDBC port
+	TLSSectionName string `json:"tlsSectionName,omitempty"`
+}
+
 // DataSourceObject - define the datasources that this Virtual Database integrates
 // +k8s:openapi-gen=true
 type DataSourceObject struct {
@@ -173,6 +724,30 @@ type DataSourceObject struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Properties"
 	Properties []corev1.EnvVar `json:"properties,omitempty"`
+	// JDBC driver of the Data Source, added to the build
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Driver"
+	Driver *DriverObject `json:"driver,omitempty"`
+	// Custom translator of the Data Source, added to the build
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Translator"
+	Translator *TranslatorObject `json:"translator,omitempty"`
+}
+
+// DriverObject - artifact of a JDBC driver
+// +k8s:openapi-gen=true
+type DriverObject struct {
+	// Maven artifact of the driver as groupId:artifactId:version
+	Maven string `json:"maven"`
+}
+
+// TranslatorObject - artifact of a custom translator
+// +k8s:openapi-gen=true
+type TranslatorObject struct {
+	// Maven artifact of the translator as groupId:artifactId:version
+	Maven string `json:"maven"`
+	// Name of the translator, must match the FOREIGN DATA WRAPPER of the server in the DDL
+	Name string `json:"name"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -182,6 +757,7 @@ type DataSourceObject struct {
 // +operator-sdk:gen-csv:customresourcedefinitions.displayName="Virtual Database Application"
 // +kubebuilder:resource:path=virtualdatabases,shortName=vdb;vdbs
 // +kubebuilder:singular=virtualdatabase
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 type VirtualDatabase struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
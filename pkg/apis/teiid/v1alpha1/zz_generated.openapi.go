@@ -13,8 +13,39 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
+		"./pkg/apis/teiid/v1alpha1.AutoscalingMetric":          schema_pkg_apis_teiid_v1alpha1_AutoscalingMetric(ref),
+		"./pkg/apis/teiid/v1alpha1.AutoscalingObject":          schema_pkg_apis_teiid_v1alpha1_AutoscalingObject(ref),
+		"./pkg/apis/teiid/v1alpha1.BlueGreenObject":            schema_pkg_apis_teiid_v1alpha1_BlueGreenObject(ref),
+		"./pkg/apis/teiid/v1alpha1.BufferStorageObject":        schema_pkg_apis_teiid_v1alpha1_BufferStorageObject(ref),
+		"./pkg/apis/teiid/v1alpha1.BuildHistoryEntry":          schema_pkg_apis_teiid_v1alpha1_BuildHistoryEntry(ref),
+		"./pkg/apis/teiid/v1alpha1.CanaryAnalysisObject":       schema_pkg_apis_teiid_v1alpha1_CanaryAnalysisObject(ref),
+		"./pkg/apis/teiid/v1alpha1.CanaryObject":               schema_pkg_apis_teiid_v1alpha1_CanaryObject(ref),
+		"./pkg/apis/teiid/v1alpha1.CanaryStatus":               schema_pkg_apis_teiid_v1alpha1_CanaryStatus(ref),
+		"./pkg/apis/teiid/v1alpha1.CanaryStep":                 schema_pkg_apis_teiid_v1alpha1_CanaryStep(ref),
+		"./pkg/apis/teiid/v1alpha1.ConnectionFactory":          schema_pkg_apis_teiid_v1alpha1_ConnectionFactory(ref),
+		"./pkg/apis/teiid/v1alpha1.ConnectionFactorySpec":      schema_pkg_apis_teiid_v1alpha1_ConnectionFactorySpec(ref),
+		"./pkg/apis/teiid/v1alpha1.DataRoleObject":             schema_pkg_apis_teiid_v1alpha1_DataRoleObject(ref),
 		"./pkg/apis/teiid/v1alpha1.DataSourceObject":           schema_pkg_apis_teiid_v1alpha1_DataSourceObject(ref),
+		"./pkg/apis/teiid/v1alpha1.DataSourceStatus":           schema_pkg_apis_teiid_v1alpha1_DataSourceStatus(ref),
+		"./pkg/apis/teiid/v1alpha1.DisruptionBudgetObject":     schema_pkg_apis_teiid_v1alpha1_DisruptionBudgetObject(ref),
+		"./pkg/apis/teiid/v1alpha1.DriverObject":               schema_pkg_apis_teiid_v1alpha1_DriverObject(ref),
+		"./pkg/apis/teiid/v1alpha1.EndpointStatus":             schema_pkg_apis_teiid_v1alpha1_EndpointStatus(ref),
+		"./pkg/apis/teiid/v1alpha1.ExposeOptionsObject":        schema_pkg_apis_teiid_v1alpha1_ExposeOptionsObject(ref),
+		"./pkg/apis/teiid/v1alpha1.GatewayReference":           schema_pkg_apis_teiid_v1alpha1_GatewayReference(ref),
+		"./pkg/apis/teiid/v1alpha1.MavenBuildObject":           schema_pkg_apis_teiid_v1alpha1_MavenBuildObject(ref),
+		"./pkg/apis/teiid/v1alpha1.NetworkPolicyObject":        schema_pkg_apis_teiid_v1alpha1_NetworkPolicyObject(ref),
+		"./pkg/apis/teiid/v1alpha1.OIDCObject":                 schema_pkg_apis_teiid_v1alpha1_OIDCObject(ref),
+		"./pkg/apis/teiid/v1alpha1.OIDCRoleMapping":            schema_pkg_apis_teiid_v1alpha1_OIDCRoleMapping(ref),
+		"./pkg/apis/teiid/v1alpha1.PermissionObject":           schema_pkg_apis_teiid_v1alpha1_PermissionObject(ref),
+		"./pkg/apis/teiid/v1alpha1.PodTemplateObject":          schema_pkg_apis_teiid_v1alpha1_PodTemplateObject(ref),
+		"./pkg/apis/teiid/v1alpha1.ProbesObject":               schema_pkg_apis_teiid_v1alpha1_ProbesObject(ref),
+		"./pkg/apis/teiid/v1alpha1.ProtocolObject":             schema_pkg_apis_teiid_v1alpha1_ProtocolObject(ref),
+		"./pkg/apis/teiid/v1alpha1.ProtocolsObject":            schema_pkg_apis_teiid_v1alpha1_ProtocolsObject(ref),
+		"./pkg/apis/teiid/v1alpha1.RolloutObject":              schema_pkg_apis_teiid_v1alpha1_RolloutObject(ref),
+		"./pkg/apis/teiid/v1alpha1.SBOMStatus":                 schema_pkg_apis_teiid_v1alpha1_SBOMStatus(ref),
+		"./pkg/apis/teiid/v1alpha1.SecurityObject":             schema_pkg_apis_teiid_v1alpha1_SecurityObject(ref),
 		"./pkg/apis/teiid/v1alpha1.Source":                     schema_pkg_apis_teiid_v1alpha1_Source(ref),
+		"./pkg/apis/teiid/v1alpha1.TranslatorObject":           schema_pkg_apis_teiid_v1alpha1_TranslatorObject(ref),
 		"./pkg/apis/teiid/v1alpha1.ValueSource":                schema_pkg_apis_teiid_v1alpha1_ValueSource(ref),
 		"./pkg/apis/teiid/v1alpha1.VirtualDatabase":            schema_pkg_apis_teiid_v1alpha1_VirtualDatabase(ref),
 		"./pkg/apis/teiid/v1alpha1.VirtualDatabaseBuildObject": schema_pkg_apis_teiid_v1alpha1_VirtualDatabaseBuildObject(ref),
@@ -23,35 +54,1435 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	}
 }
 
+func schema_pkg_apis_teiid_v1alpha1_AutoscalingMetric(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AutoscalingMetric - custom metric of the pods and the target average value",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the metric",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"targetAverageValue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target average value of the metric across the pods, a quantity such as 10 or 500m",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "targetAverageValue"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_AutoscalingObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AutoscalingObject - bounds and targets of the HorizontalPodAutoscaler",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"minReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lower limit of replicas, defaults to 1",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Upper limit of replicas",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"targetCPUUtilization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target average CPU utilization as percentage of the requested CPU, 80 when no target is given",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"targetMemoryUtilization": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Target average memory utilization as percentage of the requested memory",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"metrics": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Custom metrics of the pods, such as org_teiid_WaitingRequestsCount or org_teiid_SessionCount scraped from the JMX exporter, require an adapter that serves them through the custom metrics API",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.AutoscalingMetric"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"maxReplicas"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.AutoscalingMetric"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_BlueGreenObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BlueGreenObject - verification of the new version and retention of the previous one",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"verificationPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OData path queried on a pod of the new version before switching, ex: /odata/portfolio/CustomerZip?$top=1. The query must succeed without authentication. Only readiness is checked when omitted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retentionPeriod": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the previous version is kept after the switch, for an instant rollback. Defaults to 1h",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_BufferStorageObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BufferStorageObject - volume of the buffer manager, the disk usage of the buffer manager is bounded by its size",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ephemeral (default) for an emptyDir, PersistentVolumeClaim for one claim shared by the replicas or PerReplica for a claim per replica, which deploys the Virtual Database as a StatefulSet",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"size": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Size of the volume, ex: 10Gi",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"storageClass": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StorageClass of the claims, the default storage class when omitted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"accessMode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AccessMode of the shared claim, defaults to ReadWriteOnce. Replicas spread across nodes need ReadWriteMany",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"size"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_BuildHistoryEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "BuildHistoryEntry - image built for a version of the Virtual Database",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version of the Virtual Database",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"digest": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Digest of the Virtual Database the image was built from",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image the build pushed to the ImageStream tag, referenced by its digest",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"built": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the build completed",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"sbom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Software bill of materials of the image",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.SBOMStatus"),
+						},
+					},
+					"sbomUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Why the image has no software bill of materials",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"version", "image"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.SBOMStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_CanaryAnalysisObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CanaryAnalysisObject - Prometheus query giving the error rate of the new version",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"prometheusUrl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL of the Prometheus API, ex: https://thanos-querier.openshift-monitoring.svc:9091",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"query": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PromQL query returning the error rate of the new version, {{namespace}}, {{service}} and {{version}} are replaced. Defaults to the share of the requests processed by the new version that failed with a Teiid buffer out of disk error. The step is held while the query returns no samples",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxErrorRate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Highest acceptable error rate, ex: 0.01. Defaults to 0.05",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"prometheusUrl"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_CanaryObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CanaryObject - steps and analysis of the Canary strategy. The OData traffic of the Route or Gateway is split between the Services of the versions, JDBC and PostgreSQL stay on the active version until the promotion",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"steps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Share of the traffic sent to the new version per step, the new version is promoted after the last step. Defaults to 10% then 50%, each for 5 minutes",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.CanaryStep"),
+									},
+								},
+							},
+						},
+					},
+					"analysis": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error rate analysis of the new version, the canary is aborted when the error rate is exceeded. The canary is also aborted when a pod of the new version is not ready or restarts",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.CanaryAnalysisObject"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.CanaryAnalysisObject", "./pkg/apis/teiid/v1alpha1.CanaryStep"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_CanaryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CanaryStatus - step of the Canary rollout",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version receiving the canary traffic",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"step": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Index of the current step",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"weight": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Percentage of the OData requests sent to the version",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"stepStarted": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the current step started",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"version", "step", "weight"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_CanaryStep(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CanaryStep - share of the traffic sent to the new version and for how long",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"weight": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Percentage of the OData requests sent to the new version",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"pause": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How long the weight is kept before moving to the next step, ex: 10m. Defaults to 5m",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"weight"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_ConnectionFactory(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConnectionFactory is the Schema for the connectionfactories API",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Connection Factory specification",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ConnectionFactorySpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.ConnectionFactorySpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_ConnectionFactorySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConnectionFactorySpec defines a source type that Virtual Databases of the namespace can use in addition to the ones the operator ships with",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the source type as used in the FOREIGN DATA WRAPPER and the type of the Data Sources, defaults to the name of the resource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"driverNames": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Class names of the JDBC drivers",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"translatorName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the translator",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"dialect": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Hibernate dialect of the source",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"springBootPropertyPrefix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Prefix of the Spring Boot properties the Data Source properties are mapped to, defaults to spring.teiid.data.<name>",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"jdbc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Source is accessed through JDBC",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"dependencies": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maven artifacts of the driver and translator as groupId:artifactId:version, added to the build of the Virtual Databases using the source type",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_DataRoleObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataRoleObject - Teiid data role and the permissions granted to it",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the data role",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"groups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Identity provider groups (roles) that are mapped to this data role",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"anyAuthenticated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Assign this data role to any authenticated user",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"permissions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Permissions granted to the data role",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.PermissionObject"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.PermissionObject"},
+	}
+}
+
 func schema_pkg_apis_teiid_v1alpha1_DataSourceObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DataSourceObject - define the datasources that this Virtual Database integrates",
+				Description: "DataSourceObject - define the datasources that this Virtual Database integrates",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the Data Source",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of Data Source. ex: Oracle, PostgreSQL, MySQL, Salesforce etc.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"properties": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Properties required for Data Source connection",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
+					"driver": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JDBC driver of the Data Source, added to the build",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.DriverObject"),
+						},
+					},
+					"translator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Custom translator of the Data Source, added to the build",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.TranslatorObject"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.DriverObject", "./pkg/apis/teiid/v1alpha1.TranslatorObject", "k8s.io/api/core/v1.EnvVar"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_DataSourceStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DataSourceStatus - artifacts resolved for a Data Source",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the Data Source",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"driver": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Driver artifact as groupId:artifactId:version",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"translator": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the custom translator",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"translatorArtifact": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Translator artifact as groupId:artifactId:version",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resolved": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The artifacts were found in the maven repositories of the build, false when a repository could not be reached and the build decides",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_DisruptionBudgetObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DisruptionBudgetObject - the pods that must stay available during voluntary disruptions, only one of minAvailable or maxUnavailable can be given",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"minAvailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number or percentage of pods that must remain available",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number or percentage of pods that can be unavailable",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_DriverObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DriverObject - artifact of a JDBC driver",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maven": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maven artifact of the driver as groupId:artifactId:version",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"maven"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_EndpointStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "EndpointStatus - endpoint through which a protocol of the Virtual Database is reachable",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"protocol": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protocol of the endpoint",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"internalUrl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL to reach the endpoint from inside of the cluster",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"externalUrl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL to reach the endpoint from outside of the cluster",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the protocol is secured with TLS",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"protocol"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_ExposeOptionsObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ExposeOptionsObject - options of the Route, Ingress or Gateway routes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host name to expose the Virtual Database on, generated by the cluster when omitted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ingressClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Class of the Ingress controller to use",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations added to the Route, Ingress or Gateway routes",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"tlsSecretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the Secret with the TLS certificate (tls.crt, tls.key) for the Ingress",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"gateway": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Gateway to attach the HTTPRoute and TLSRoutes to, required for Gateway",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.GatewayReference"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.GatewayReference"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_GatewayReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GatewayReference - reference to a Gateway API Gateway",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the Gateway",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the Gateway, defaults to the namespace of the Virtual Database",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sectionName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Listener of the Gateway used by the HTTPRoute",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tlsSectionName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Listener of the Gateway, with TLS passthrough mode, used by the TLSRoute of the secure JDBC port",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_MavenBuildObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MavenBuildObject - customizations of the Maven build of the service image",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"args": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Arguments appended to the Maven command line",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"profiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Profiles activated in the build",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"properties": {
+						SchemaProps: spec.SchemaProps{
+							Description: "System properties of the build, these take precedence over the defaults of the operator",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"jdk": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Java version the sources are compiled for, 1.8 when omitted",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"builderImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "S2I image the service image is built with instead of the builder image of the operator, for example one with a newer JDK. Its Maven repository is not pre-populated",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_NetworkPolicyObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NetworkPolicyObject - peers allowed to reach the ports of the Virtual Database",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"from": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Peers allowed to reach the OData, JDBC and PostgreSQL ports, only the pods in the same namespace when omitted",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/networking/v1.NetworkPolicyPeer"),
+									},
+								},
+							},
+						},
+					},
+					"ingressControllers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Peers of the routers, Ingress and Gateway controllers allowed to reach the exposed ports. When omitted the namespaces labelled network.openshift.io/policy-group=ingress for Routes and all the namespaces for Ingress and Gateway, whose controllers carry no common label",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/networking/v1.NetworkPolicyPeer"),
+									},
+								},
+							},
+						},
+					},
+					"metrics": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Peers allowed to scrape the metrics, the namespaces labelled network.openshift.io/policy-group=monitoring when omitted",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/networking/v1.NetworkPolicyPeer"),
+									},
+								},
+							},
+						},
+					},
+					"jolokia": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Peers allowed to reach the jolokia agent, none when omitted",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/networking/v1.NetworkPolicyPeer"),
+									},
+								},
+							},
+						},
+					},
+					"egress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Egress restricts the outgoing traffic to DNS, the pods in the same namespace, the hosts of the jdbc-url of the data sources and the OIDC issuer. Hosts outside of the cluster are resolved to IP addresses when the spec changes, hosts whose addresses change must be allowed through additionalEgress",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"additionalEgress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Additional egress rules, when egress is restricted",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/networking/v1.NetworkPolicyEgressRule"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/networking/v1.NetworkPolicyEgressRule", "k8s.io/api/networking/v1.NetworkPolicyPeer"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_OIDCObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OIDCObject - OpenID Connect identity provider configuration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"issuerUrl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Issuer URL of the identity provider, either the Keycloak auth server URL (https://host/auth) or the realm issuer URL (https://host/auth/realms/myrealm)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"realm": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Realm name, optional when it is part of the issuer URL",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"clientId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Client ID registered with the identity provider",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret key that holds the client secret, not needed for public clients",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+					"caCertificateSecretRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secret key that holds the PEM encoded CA certificate(s) of the identity provider, added to the truststore",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+					"roleMapping": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Defines how the roles in the token are mapped to the user",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.OIDCRoleMapping"),
+						},
+					},
+				},
+				Required: []string{"issuerUrl", "clientId"},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.OIDCRoleMapping", "k8s.io/api/core/v1.SecretKeySelector"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_OIDCRoleMapping(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OIDCRoleMapping - defines how the user principal and roles are read from the token",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"useResourceRoles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Use the client (resource) roles of the token instead of the realm roles",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"principalAttribute": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Token claim used as the user name, ex: preferred_username. Defaults to sub",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_PermissionObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PermissionObject - permission on a schema or a view",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"schema": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the schema",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"view": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the view or table in the schema, when omitted the permission applies to whole schema",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"privileges": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Privileges granted, one of SELECT, INSERT, UPDATE, DELETE, EXECUTE, ALTER, DROP or ALL PRIVILEGES. Defaults to SELECT",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"schema"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_PodTemplateObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PodTemplateObject - volumes, containers and settings added to the generated pods",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"labels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels added to the pods",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations added to the pods",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"volumes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Volumes added to the pods",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Volume"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMounts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Volumes mounted into the Virtual Database container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.VolumeMount"),
+									},
+								},
+							},
+						},
+					},
+					"initContainers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Init containers run before the Virtual Database container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Container"),
+									},
+								},
+							},
+						},
+					},
+					"sidecars": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Containers run next to the Virtual Database container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Container"),
+									},
+								},
+							},
+						},
+					},
+					"securityContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Security context of the pods",
+							Ref:         ref("k8s.io/api/core/v1.PodSecurityContext"),
+						},
+					},
+					"containerSecurityContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Security context of the Virtual Database container",
+							Ref:         ref("k8s.io/api/core/v1.SecurityContext"),
+						},
+					},
+					"serviceAccountName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service account the pods run as",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secrets used to pull the images of the sidecars and init containers",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.LocalObjectReference"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.LocalObjectReference", "k8s.io/api/core/v1.PodSecurityContext", "k8s.io/api/core/v1.SecurityContext", "k8s.io/api/core/v1.Volume", "k8s.io/api/core/v1.VolumeMount"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_ProbesObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProbesObject - overrides of the probes of the Virtual Database container, a probe without a handler keeps the default health check and only changes the timings",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"liveness": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Liveness probe, defaults to the liveness health group of the actuator",
+							Ref:         ref("k8s.io/api/core/v1.Probe"),
+						},
+					},
+					"readiness": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Readiness probe, defaults to the readiness health group of the actuator",
+							Ref:         ref("k8s.io/api/core/v1.Probe"),
+						},
+					},
+					"startup": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Startup probe, defaults to the liveness health group allowing ten minutes to start",
+							Ref:         ref("k8s.io/api/core/v1.Probe"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.Probe"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_ProtocolObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProtocolObject - the secure and insecure listeners of a protocol",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secure listener using TLS",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"insecure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Insecure plain text listener",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_ProtocolsObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProtocolsObject - the listeners of the Virtual Database",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"jdbc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Teiid JDBC, secure on port 31443 and insecure on port 31000, both enabled when omitted",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ProtocolObject"),
+						},
+					},
+					"pg": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PostgreSQL, secure on port 5433 and insecure on port 5432, both enabled when omitted",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ProtocolObject"),
+						},
+					},
+					"odata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OData, secure https on port 8443 or insecure http on port 8080, only one of them can be enabled. When disabled port 8080 still serves the health checks, but is not exposed",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ProtocolObject"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.ProtocolObject"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_RolloutObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RolloutObject - strategy used to replace the running version of the Virtual Database",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"strategy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the Data Source",
+							Description: "Rolling (default) updates the Deployment in place, BlueGreen deploys every version as its own Deployment and switches the clients over once it is ready, Canary shifts the OData traffic to the new version in steps",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"type": {
+					"blueGreen": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Type of Data Source. ex: Oracle, PostgreSQL, MySQL, Salesforce etc.",
+							Description: "Options of the BlueGreen strategy, the retention period also applies to the Canary strategy",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.BlueGreenObject"),
+						},
+					},
+					"canary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Options of the Canary strategy",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.CanaryObject"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.BlueGreenObject", "./pkg/apis/teiid/v1alpha1.CanaryObject"},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_SBOMStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SBOMStatus - CycloneDX software bill of materials of an image and the versions resolved by its build",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"configMap": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMap holding the gzipped CycloneDX JSON document under the key bom.json.gz",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"properties": {
+					"components": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Properties required for Data Source connection",
+							Description: "Number of components in the image",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"teiidSpringBootVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resolved version of teiid-spring-boot",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"springBootVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resolved version of Spring Boot",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"drivers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drivers of the data sources as groupId:artifactId:version",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Ref: ref("k8s.io/api/core/v1.EnvVar"),
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"configMap"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_teiid_v1alpha1_SecurityObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SecurityObject - defines how the clients of the Virtual Database are authenticated and authorized",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oidc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OpenID Connect (Keycloak) based authentication",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.OIDCObject"),
+						},
+					},
+					"roles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data roles of the Virtual Database, mapped from the identity provider groups",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.DataRoleObject"),
 									},
 								},
 							},
@@ -61,7 +1492,7 @@ func schema_pkg_apis_teiid_v1alpha1_DataSourceObject(ref common.ReferenceCallbac
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/api/core/v1.EnvVar"},
+			"./pkg/apis/teiid/v1alpha1.DataRoleObject", "./pkg/apis/teiid/v1alpha1.OIDCObject"},
 	}
 }
 
@@ -135,6 +1566,34 @@ func schema_pkg_apis_teiid_v1alpha1_Source(ref common.ReferenceCallback) common.
 	}
 }
 
+func schema_pkg_apis_teiid_v1alpha1_TranslatorObject(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TranslatorObject - artifact of a custom translator",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maven": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maven artifact of the translator as groupId:artifactId:version",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the translator, must match the FOREIGN DATA WRAPPER of the server in the DDL",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"maven", "name"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_teiid_v1alpha1_ValueSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -234,11 +1693,17 @@ func schema_pkg_apis_teiid_v1alpha1_VirtualDatabaseBuildObject(ref common.Refere
 							Ref:         ref("./pkg/apis/teiid/v1alpha1.Source"),
 						},
 					},
+					"maven": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Maven arguments, profiles and properties of the build, merged with the defaults of the operator",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.MavenBuildObject"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/teiid/v1alpha1.Source", "k8s.io/api/core/v1.EnvVar"},
+			"./pkg/apis/teiid/v1alpha1.MavenBuildObject", "./pkg/apis/teiid/v1alpha1.Source", "k8s.io/api/core/v1.EnvVar"},
 	}
 }
 
@@ -303,9 +1768,79 @@ func schema_pkg_apis_teiid_v1alpha1_VirtualDatabaseSpec(ref common.ReferenceCall
 					},
 					"expose": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Defines the services (LoadBalancer, NodePort, 3scale) to expose",
+							Description: "Defines the services (LoadBalancer, NodePort, Route, Ingress, Gateway, 3scale) to expose",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"exposeOptions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Host, TLS and other options of the Route, Ingress or Gateway routes that expose the Virtual Database",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ExposeOptionsObject"),
+						},
+					},
+					"security": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Security configuration for the Virtual Database",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.SecurityObject"),
+						},
+					},
+					"networkPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NetworkPolicy restricts who can reach the Virtual Database, no NetworkPolicy is generated when omitted",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.NetworkPolicyObject"),
+						},
+					},
+					"protocols": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protocols the Virtual Database listens on, JDBC and PostgreSQL are served with and without TLS and OData without TLS when omitted",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ProtocolsObject"),
+						},
+					},
+					"autoscaling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Autoscaling of the Virtual Database, when configured replicas is managed by the HorizontalPodAutoscaler through the scale subresource",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.AutoscalingObject"),
+						},
+					},
+					"disruptionBudget": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PodDisruptionBudget generated when there is more than one replica, maxUnavailable of 1 when omitted",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.DisruptionBudgetObject"),
+						},
+					},
+					"affinity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scheduling constraints of the pods",
+							Ref:         ref("k8s.io/api/core/v1.Affinity"),
+						},
+					},
+					"tolerations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tolerations of the pods",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Toleration"),
+									},
+								},
+							},
+						},
+					},
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Labels of the nodes the pods can be scheduled on",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Type:   []string{"string"},
@@ -315,12 +1850,63 @@ func schema_pkg_apis_teiid_v1alpha1_VirtualDatabaseSpec(ref common.ReferenceCall
 							},
 						},
 					},
+					"topologySpreadConstraints": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How the pods are spread across zones, nodes and other topology domains",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.TopologySpreadConstraint"),
+									},
+								},
+							},
+						},
+					},
+					"priorityClassName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PriorityClass of the pods",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Additions to the pods of the Virtual Database, the settings managed by the operator take precedence",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.PodTemplateObject"),
+						},
+					},
+					"probes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Liveness, readiness and startup probes of the Virtual Database container",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.ProbesObject"),
+						},
+					},
+					"bufferStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Volume the buffer manager spills large intermediate results to, the container scratch space when omitted",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.BufferStorageObject"),
+						},
+					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How a new version of the Virtual Database replaces the running one",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.RolloutObject"),
+						},
+					},
+					"rollbackTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version from status.history to redeploy without rebuilding, cleared once the rollback started",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"build"},
 			},
 		},
 		Dependencies: []string{
-			"./pkg/apis/teiid/v1alpha1.DataSourceObject", "./pkg/apis/teiid/v1alpha1.VirtualDatabaseBuildObject", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.ResourceRequirements"},
+			"./pkg/apis/teiid/v1alpha1.AutoscalingObject", "./pkg/apis/teiid/v1alpha1.BufferStorageObject", "./pkg/apis/teiid/v1alpha1.DataSourceObject", "./pkg/apis/teiid/v1alpha1.DisruptionBudgetObject", "./pkg/apis/teiid/v1alpha1.ExposeOptionsObject", "./pkg/apis/teiid/v1alpha1.NetworkPolicyObject", "./pkg/apis/teiid/v1alpha1.PodTemplateObject", "./pkg/apis/teiid/v1alpha1.ProbesObject", "./pkg/apis/teiid/v1alpha1.ProtocolsObject", "./pkg/apis/teiid/v1alpha1.RolloutObject", "./pkg/apis/teiid/v1alpha1.SecurityObject", "./pkg/apis/teiid/v1alpha1.VirtualDatabaseBuildObject", "k8s.io/api/core/v1.Affinity", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.Toleration", "k8s.io/api/core/v1.TopologySpreadConstraint"},
 	}
 }
 
@@ -366,6 +1952,19 @@ func schema_pkg_apis_teiid_v1alpha1_VirtualDatabaseStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"endpoints": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Endpoints through which the protocols of the vdb are reachable by the clients",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.EndpointStatus"),
+									},
+								},
+							},
+						},
+					},
 					"version": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Deployed vdb version.",
@@ -380,8 +1979,89 @@ func schema_pkg_apis_teiid_v1alpha1_VirtualDatabaseStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"replicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of pods of the Virtual Database",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"readyReplicas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of pods of the Virtual Database that are ready",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Label selector of the pods, used by the scale subresource",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"activeVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version the clients are routed to with the BlueGreen rollout strategy",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"previousVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version replaced by the active version, kept for a rollback until PreviousVersionExpiry. Empty for the Deployment of the Rolling strategy",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"previousVersionExpiry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the Deployment of the previous version is removed",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"verificationFailed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time the verification of the deploying version first failed, it is retried until the verification timeout",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"canary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Progress of the Canary rollout of the new version",
+							Ref:         ref("./pkg/apis/teiid/v1alpha1.CanaryStatus"),
+						},
+					},
+					"history": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Successful builds of the Virtual Database, most recent last, that can be rolled back to",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.BuildHistoryEntry"),
+									},
+								},
+							},
+						},
+					},
+					"dataSources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Drivers and translators resolved for the Data Sources",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./pkg/apis/teiid/v1alpha1.DataSourceStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"./pkg/apis/teiid/v1alpha1.BuildHistoryEntry", "./pkg/apis/teiid/v1alpha1.CanaryStatus", "./pkg/apis/teiid/v1alpha1.DataSourceStatus", "./pkg/apis/teiid/v1alpha1.EndpointStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
@@ -0,0 +1,189 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// default target CPU utilization when no target is configured
+const defaultTargetCPUUtilization = int32(80)
+
+// NewAutoscalerAction creates a new autoscaler action
+func NewAutoscalerAction() Action {
+	return &autoscalerAction{}
+}
+
+type autoscalerAction struct {
+	baseAction
+}
+
+// Name returns a common name of the action
+func (action *autoscalerAction) Name() string {
+	return "AutoscalerAction"
+}
+
+// CanHandle tells whether this action can handle the virtualdatabase
+func (action *autoscalerAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying || vdb.Status.Phase == v1alpha1.ReconcilerPhaseRunning
+}
+
+// Handle handles the virtualdatabase, the HorizontalPodAutoscaler follows spec.autoscaling and is removed
+// when it is omitted
+func (action *autoscalerAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	if !isAutoscalingEnabled(vdb) {
+		return action.removeAutoscaler(ctx, vdb, r)
+	}
+
+	desired, err := buildAutoscaler(vdb)
+	if err != nil {
+		vdb.Status.Failure = err.Error()
+		return err
+	}
+
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.client, hpa, func() error {
+		mergeMetadata(&hpa.ObjectMeta, desired.ObjectMeta)
+		hpa.Spec = desired.Spec
+		return controllerutil.SetControllerReference(vdb, hpa, r.client.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("HorizontalPodAutoscaler ", result, ":", hpa.Name)
+	}
+	return nil
+}
+
+func (action *autoscalerAction) removeAutoscaler(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: vdb.ObjectMeta.Name, Namespace: vdb.ObjectMeta.Namespace}, hpa)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(hpa, vdb) {
+		return nil
+	}
+	if err = r.client.Delete(ctx, hpa); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	log.Info("HorizontalPodAutoscaler removed:", hpa.Name)
+	return nil
+}
+
+// isAutoscalingEnabled tells whether the replicas are managed by the HorizontalPodAutoscaler
+func isAutoscalingEnabled(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Spec.Autoscaling != nil
+}
+
+// buildAutoscaler returns the HorizontalPodAutoscaler of the vdb
+func buildAutoscaler(vdb *v1alpha1.VirtualDatabase) (autoscalingv2beta2.HorizontalPodAutoscaler, error) {
+	spec := vdb.Spec.Autoscaling
+
+	minReplicas := int32(1)
+	if spec.MinReplicas != nil {
+		minReplicas = *spec.MinReplicas
+	}
+	if minReplicas < 1 || spec.MaxReplicas < minReplicas {
+		return autoscalingv2beta2.HorizontalPodAutoscaler{},
+			fmt.Errorf("spec.autoscaling.maxReplicas %d must be at least minReplicas %d, which must be at least 1", spec.MaxReplicas, minReplicas)
+	}
+
+	metrics := []autoscalingv2beta2.MetricSpec{}
+	if spec.TargetCPUUtilization != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, *spec.TargetCPUUtilization))
+	}
+	if spec.TargetMemoryUtilization != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceMemory, *spec.TargetMemoryUtilization))
+	}
+	for _, metric := range spec.Metrics {
+		if metric.Name == "" {
+			return autoscalingv2beta2.HorizontalPodAutoscaler{}, fmt.Errorf("name is required for the metrics of spec.autoscaling")
+		}
+		value, err := resource.ParseQuantity(metric.TargetAverageValue)
+		if err != nil {
+			return autoscalingv2beta2.HorizontalPodAutoscaler{},
+				fmt.Errorf("invalid targetAverageValue %s of metric %s in spec.autoscaling, %s", metric.TargetAverageValue, metric.Name, err)
+		}
+		metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.PodsMetricSourceType,
+			Pods: &autoscalingv2beta2.PodsMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{Name: metric.Name},
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:         autoscalingv2beta2.AverageValueMetricType,
+					AverageValue: &value,
+				},
+			},
+		})
+	}
+	if len(metrics) == 0 {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, defaultTargetCPUUtilization))
+	}
+
+	hpa := autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vdb.ObjectMeta.Name,
+			Namespace: vdb.ObjectMeta.Namespace,
+			Labels: map[string]string{
+				"app":                      vdb.ObjectMeta.Name,
+				"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name,
+				"teiid.io/type":            "VirtualDatabase",
+			},
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			// scales the vdb through the scale subresource, the replicas are then carried over to the Deployment
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "VirtualDatabase",
+				Name:       vdb.ObjectMeta.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: spec.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+	hpa.SetGroupVersionKind(autoscalingv2beta2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"))
+	return hpa, nil
+}
+
+func resourceMetric(name corev1.ResourceName, utilization int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuildAutoscaler(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.Autoscaling = &v1alpha1.AutoscalingObject{MaxReplicas: 3}
+	assert.True(t, isAutoscalingEnabled(vdb))
+
+	hpa, err := buildAutoscaler(vdb)
+	assert.Nil(t, err)
+	assert.Equal(t, "dv", hpa.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, "VirtualDatabase", hpa.Spec.ScaleTargetRef.Kind)
+	assert.Equal(t, "teiid.io/v1alpha1", hpa.Spec.ScaleTargetRef.APIVersion)
+	assert.Equal(t, int32(1), *hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(3), hpa.Spec.MaxReplicas)
+	assert.Equal(t, 1, len(hpa.Spec.Metrics))
+	assert.Equal(t, corev1.ResourceCPU, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, int32(80), *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+
+	min := int32(2)
+	memory := int32(70)
+	vdb.Spec.Autoscaling = &v1alpha1.AutoscalingObject{
+		MinReplicas:             &min,
+		MaxReplicas:             5,
+		TargetMemoryUtilization: &memory,
+		Metrics: []v1alpha1.AutoscalingMetric{
+			{Name: "org_teiid_WaitingRequestsCount", TargetAverageValue: "10"},
+		},
+	}
+	hpa, err = buildAutoscaler(vdb)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), *hpa.Spec.MinReplicas)
+	assert.Equal(t, 2, len(hpa.Spec.Metrics))
+	assert.Equal(t, corev1.ResourceMemory, hpa.Spec.Metrics[0].Resource.Name)
+	assert.Equal(t, autoscalingv2beta2.PodsMetricSourceType, hpa.Spec.Metrics[1].Type)
+	assert.Equal(t, "org_teiid_WaitingRequestsCount", hpa.Spec.Metrics[1].Pods.Metric.Name)
+	assert.Equal(t, "10", hpa.Spec.Metrics[1].Pods.Target.AverageValue.String())
+
+	vdb.Spec.Autoscaling.Metrics[0].TargetAverageValue = "ten"
+	_, err = buildAutoscaler(vdb)
+	assert.NotNil(t, err)
+
+	vdb.Spec.Autoscaling.Metrics = nil
+	vdb.Spec.Autoscaling.MaxReplicas = 1
+	_, err = buildAutoscaler(vdb)
+	assert.NotNil(t, err)
+}
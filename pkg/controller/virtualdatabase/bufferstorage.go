@@ -0,0 +1,244 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	bufferVolumeName = "buffer"
+	bufferDirectory  = "/deployments/buffer"
+)
+
+// NewBufferStorageAction creates a new buffer storage action
+func NewBufferStorageAction() Action {
+	return &bufferStorageAction{}
+}
+
+type bufferStorageAction struct {
+	baseAction
+}
+
+// Name returns a common name of the action
+func (action *bufferStorageAction) Name() string {
+	return "BufferStorageAction"
+}
+
+// CanHandle tells whether this action can handle the virtualdatabase
+func (action *bufferStorageAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Status.Phase == v1alpha1.ReconcilerPhaseKeystoreCreated || vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseRunning
+}
+
+// Handle handles the virtualdatabase, the shared claim exists only for the PersistentVolumeClaim storage type. The
+// claims of the PerReplica storage type are created by the StatefulSet
+func (action *bufferStorageAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	if bufferStorageType(vdb) != v1alpha1.BufferStoragePersistentVolumeClaim {
+		return action.removeClaim(ctx, vdb, r)
+	}
+
+	desired, err := buildBufferClaim(vdb)
+	if err != nil {
+		vdb.Status.Failure = err.Error()
+		return err
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.client, pvc, func() error {
+		mergeMetadata(&pvc.ObjectMeta, desired.ObjectMeta)
+		if pvc.CreationTimestamp.IsZero() {
+			pvc.Spec = desired.Spec
+		} else {
+			// only the size can change once the claim is bound, and only to grow the volume
+			size := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+			if size.Cmp(pvc.Spec.Resources.Requests[corev1.ResourceStorage]) > 0 {
+				pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+			}
+		}
+		return controllerutil.SetControllerReference(vdb, pvc, r.client.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("PersistentVolumeClaim ", result, ":", pvc.Name)
+	}
+	return nil
+}
+
+func (action *bufferStorageAction) removeClaim(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) error {
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: bufferClaimName(vdb), Namespace: vdb.ObjectMeta.Namespace}, pvc)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(pvc, vdb) {
+		return nil
+	}
+	if err = r.client.Delete(ctx, pvc); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	log.Info("PersistentVolumeClaim removed:", pvc.Name)
+	return nil
+}
+
+func bufferClaimName(vdb *v1alpha1.VirtualDatabase) string {
+	return vdb.ObjectMeta.Name + "-" + bufferVolumeName
+}
+
+// bufferStorageType returns the storage type, empty when no buffer storage is configured
+func bufferStorageType(vdb *v1alpha1.VirtualDatabase) v1alpha1.BufferStorageType {
+	if vdb.Spec.BufferStorage == nil {
+		return ""
+	}
+	if vdb.Spec.BufferStorage.Type == "" {
+		return v1alpha1.BufferStorageEphemeral
+	}
+	return vdb.Spec.BufferStorage.Type
+}
+
+// usesStatefulSet tells whether the Virtual Database is deployed as a StatefulSet to get a claim per replica
+func usesStatefulSet(vdb *v1alpha1.VirtualDatabase) bool {
+	return bufferStorageType(vdb) == v1alpha1.BufferStoragePerReplica
+}
+
+func validateBufferStorage(vdb *v1alpha1.VirtualDatabase) error {
+	if vdb.Spec.BufferStorage == nil {
+		return nil
+	}
+	_, err := bufferStorageSize(vdb)
+	return err
+}
+
+func bufferStorageSize(vdb *v1alpha1.VirtualDatabase) (resource.Quantity, error) {
+	size, err := resource.ParseQuantity(vdb.Spec.BufferStorage.Size)
+	if err != nil {
+		return size, fmt.Errorf("invalid spec.bufferStorage.size %q: %v", vdb.Spec.BufferStorage.Size, err)
+	}
+	if size.Sign() <= 0 {
+		return size, fmt.Errorf("spec.bufferStorage.size must be positive")
+	}
+	return size, nil
+}
+
+// buildBufferClaim returns the claim shared by the replicas
+func buildBufferClaim(vdb *v1alpha1.VirtualDatabase) (corev1.PersistentVolumeClaim, error) {
+	spec, err := bufferClaimSpec(vdb)
+	if err != nil {
+		return corev1.PersistentVolumeClaim{}, err
+	}
+	accessMode := vdb.Spec.BufferStorage.AccessMode
+	if accessMode == "" {
+		accessMode = corev1.ReadWriteOnce
+	}
+	spec.AccessModes = []corev1.PersistentVolumeAccessMode{accessMode}
+
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bufferClaimName(vdb),
+			Namespace: vdb.ObjectMeta.Namespace,
+			Labels:    matchLabels(vdb.ObjectMeta.Name),
+		},
+		Spec: spec,
+	}
+	pvc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+	return pvc, nil
+}
+
+func bufferClaimSpec(vdb *v1alpha1.VirtualDatabase) (corev1.PersistentVolumeClaimSpec, error) {
+	size, err := bufferStorageSize(vdb)
+	if err != nil {
+		return corev1.PersistentVolumeClaimSpec{}, err
+	}
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+		},
+	}
+	if vdb.Spec.BufferStorage.StorageClass != "" {
+		storageClass := vdb.Spec.BufferStorage.StorageClass
+		spec.StorageClassName = &storageClass
+	}
+	return spec, nil
+}
+
+// applyBufferStorage mounts the buffer volume into the Virtual Database container, the volume of the PerReplica
+// storage type comes from the claim template of the StatefulSet
+func applyBufferStorage(vdb *v1alpha1.VirtualDatabase, spec *corev1.PodSpec) error {
+	storageType := bufferStorageType(vdb)
+	if storageType == "" {
+		return nil
+	}
+	size, err := bufferStorageSize(vdb)
+	if err != nil {
+		return err
+	}
+
+	mount := corev1.VolumeMount{Name: bufferVolumeName, MountPath: bufferDirectory}
+	switch storageType {
+	case v1alpha1.BufferStorageEphemeral:
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         bufferVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &size}},
+		})
+	case v1alpha1.BufferStoragePersistentVolumeClaim:
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name: bufferVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: bufferClaimName(vdb)},
+			},
+		})
+		// replicas must not share the buffer files
+		mount.SubPathExpr = "$(TEIID_PODNAME)"
+	}
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, mount)
+	return nil
+}
+
+// bufferStorageEnvironments points the buffer manager to the buffer volume, leaving a tenth of the volume free
+func bufferStorageEnvironments(vdb *v1alpha1.VirtualDatabase) []corev1.EnvVar {
+	if vdb.Spec.BufferStorage == nil {
+		return nil
+	}
+	size, err := bufferStorageSize(vdb)
+	if err != nil {
+		return nil
+	}
+	maxBufferSpace := size.Value() / (1024 * 1024) * 9 / 10
+	return []corev1.EnvVar{
+		{Name: "TEIID_BUFFERDIRECTORY", Value: bufferDirectory},
+		{Name: "TEIID_MAXBUFFERSPACE", Value: strconv.FormatInt(maxBufferSpace, 10)},
+	}
+}
@@ -0,0 +1,137 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/envvar"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func bufferStorageVdb(storageType v1alpha1.BufferStorageType) *v1alpha1.VirtualDatabase {
+	vdb := testVdb()
+	vdb.Spec.BufferStorage = &v1alpha1.BufferStorageObject{Type: storageType, Size: "10Gi", StorageClass: "fast"}
+	return vdb
+}
+
+func bufferPodSpec() corev1.PodSpec {
+	return corev1.PodSpec{Containers: []corev1.Container{{Name: "dv"}}}
+}
+
+func TestNoBufferStorage(t *testing.T) {
+	vdb := testVdb()
+	spec := bufferPodSpec()
+	assert.Nil(t, applyBufferStorage(vdb, &spec))
+	assert.Equal(t, 0, len(spec.Volumes))
+	assert.Equal(t, 0, len(spec.Containers[0].VolumeMounts))
+	assert.Equal(t, 0, len(bufferStorageEnvironments(vdb)))
+	assert.False(t, usesStatefulSet(vdb))
+}
+
+func TestEphemeralBufferStorage(t *testing.T) {
+	vdb := bufferStorageVdb("")
+	spec := bufferPodSpec()
+	assert.Nil(t, applyBufferStorage(vdb, &spec))
+
+	assert.Equal(t, "buffer", spec.Volumes[0].Name)
+	assert.Equal(t, "10Gi", spec.Volumes[0].EmptyDir.SizeLimit.String())
+	assert.Equal(t, bufferDirectory, spec.Containers[0].VolumeMounts[0].MountPath)
+	assert.Equal(t, "", spec.Containers[0].VolumeMounts[0].SubPathExpr)
+
+	envs := bufferStorageEnvironments(vdb)
+	assert.Equal(t, bufferDirectory, envvar.Get(envs, "TEIID_BUFFERDIRECTORY").Value)
+	assert.Equal(t, "9216", envvar.Get(envs, "TEIID_MAXBUFFERSPACE").Value)
+}
+
+func TestSharedBufferClaim(t *testing.T) {
+	vdb := bufferStorageVdb(v1alpha1.BufferStoragePersistentVolumeClaim)
+	spec := bufferPodSpec()
+	assert.Nil(t, applyBufferStorage(vdb, &spec))
+
+	assert.Equal(t, "dv-buffer", spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, "$(TEIID_PODNAME)", spec.Containers[0].VolumeMounts[0].SubPathExpr)
+
+	pvc, err := buildBufferClaim(vdb)
+	assert.Nil(t, err)
+	assert.Equal(t, "dv-buffer", pvc.Name)
+	assert.Equal(t, "fast", *pvc.Spec.StorageClassName)
+	assert.Equal(t, corev1.ReadWriteOnce, pvc.Spec.AccessModes[0])
+	storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, "10Gi", storage.String())
+
+	vdb.Spec.BufferStorage.AccessMode = corev1.ReadWriteMany
+	pvc, _ = buildBufferClaim(vdb)
+	assert.Equal(t, corev1.ReadWriteMany, pvc.Spec.AccessModes[0])
+}
+
+func TestPerReplicaBufferStorage(t *testing.T) {
+	vdb := bufferStorageVdb(v1alpha1.BufferStoragePerReplica)
+	assert.True(t, usesStatefulSet(vdb))
+
+	replicas := int32(2)
+	dc := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "dv", Namespace: "myproject"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: matchLabels("dv")},
+		},
+	}
+	dc.Spec.Template.Spec = bufferPodSpec()
+	assert.Nil(t, applyBufferStorage(vdb, &dc.Spec.Template.Spec))
+	assert.Equal(t, 0, len(dc.Spec.Template.Spec.Volumes))
+	assert.Equal(t, "buffer", dc.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name)
+
+	sts, err := buildStatefulSet(vdb, dc)
+	assert.Nil(t, err)
+	assert.Equal(t, "dv", sts.Name)
+	assert.Equal(t, int32(2), *sts.Spec.Replicas)
+	assert.Equal(t, "buffer", sts.Spec.VolumeClaimTemplates[0].Name)
+	assert.Equal(t, "fast", *sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName)
+	assert.Equal(t, appsv1.ParallelPodManagement, sts.Spec.PodManagementPolicy)
+}
+
+func TestStatefulSetReadyState(t *testing.T) {
+	replicas := int32(2)
+	sts := appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	sts.Spec.Replicas = &replicas
+	sts.Status.ObservedGeneration = 2
+	sts.Status.ReadyReplicas = 1
+	assert.False(t, isStatefulSetInReadyState(sts))
+
+	sts.Status.ReadyReplicas = 2
+	assert.True(t, isStatefulSetInReadyState(sts))
+
+	sts.Generation = 3
+	assert.False(t, isStatefulSetInReadyState(sts))
+}
+
+func TestValidateBufferStorage(t *testing.T) {
+	vdb := bufferStorageVdb(v1alpha1.BufferStorageEphemeral)
+	assert.Nil(t, validateBufferStorage(vdb))
+
+	vdb.Spec.BufferStorage.Size = "lots"
+	assert.NotNil(t, validateBufferStorage(vdb))
+
+	vdb.Spec.BufferStorage.Size = "0"
+	assert.NotNil(t, validateBufferStorage(vdb))
+}
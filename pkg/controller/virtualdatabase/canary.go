@@ -0,0 +1,333 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	oroutev1 "github.com/openshift/api/route/v1"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultCanaryPause  = 5 * time.Minute
+	defaultMaxErrorRate = 0.05
+	defaultCanaryQuery  = `sum(rate(org_teiid_TotalOutOfDiskErrors{namespace="{{namespace}}",service="{{service}}"}[5m]))` +
+		` / sum(rate(org_teiid_TotalRequestsProcessed{namespace="{{namespace}}",service="{{service}}"}[5m]))`
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+// errNoSamples the query returned no samples, ex: the new version did not process any request yet
+var errNoSamples = errors.New("no samples")
+
+func canarySteps(vdb *v1alpha1.VirtualDatabase) []v1alpha1.CanaryStep {
+	if vdb.Spec.Rollout.Canary == nil || len(vdb.Spec.Rollout.Canary.Steps) == 0 {
+		return []v1alpha1.CanaryStep{{Weight: 10}, {Weight: 50}}
+	}
+	return vdb.Spec.Rollout.Canary.Steps
+}
+
+func canaryPause(step v1alpha1.CanaryStep) (time.Duration, error) {
+	if step.Pause == "" {
+		return defaultCanaryPause, nil
+	}
+	pause, err := time.ParseDuration(step.Pause)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pause %q of the canary step: %v", step.Pause, err)
+	}
+	return pause, nil
+}
+
+func maxErrorRate(analysis *v1alpha1.CanaryAnalysisObject) (float64, error) {
+	if analysis.MaxErrorRate == "" {
+		return defaultMaxErrorRate, nil
+	}
+	rate, err := strconv.ParseFloat(analysis.MaxErrorRate, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxErrorRate %q of the canary analysis: %v", analysis.MaxErrorRate, err)
+	}
+	return rate, nil
+}
+
+func validateCanary(vdb *v1alpha1.VirtualDatabase) error {
+	if !isCanary(vdb) {
+		return nil
+	}
+	for _, step := range canarySteps(vdb) {
+		if _, err := canaryPause(step); err != nil {
+			return err
+		}
+	}
+	if analysis := vdb.Spec.Rollout.Canary.Analysis; analysis != nil {
+		if _, err := url.Parse(analysis.PrometheusURL); err != nil || analysis.PrometheusURL == "" {
+			return fmt.Errorf("invalid prometheusUrl %q of the canary analysis", analysis.PrometheusURL)
+		}
+		if _, err := maxErrorRate(analysis); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startCanary sends the traffic share of the first step to the new version
+func startCanary(vdb *v1alpha1.VirtualDatabase, now time.Time) {
+	steps := canarySteps(vdb)
+	vdb.Status.Canary = &v1alpha1.CanaryStatus{
+		Version:     vdb.Status.Version,
+		Step:        0,
+		Weight:      steps[0].Weight,
+		StepStarted: metav1.NewTime(now),
+	}
+	log.Info("Canary of ", vdb.ObjectMeta.Name, " version ", vdb.Status.Version, " started at ", steps[0].Weight, "%")
+}
+
+// progressCanary aborts the canary when the new version is unhealthy, otherwise moves to the next step once the
+// pause of the current one elapsed and promotes the new version after the last step. The step is held as long as the
+// health of the new version is unknown
+func (action *deploymentAction) progressCanary(ctx context.Context, vdb *v1alpha1.VirtualDatabase, dc *appsv1.Deployment,
+	r *ReconcileVirtualDatabase, now time.Time) error {
+
+	reason, err := canaryFailure(ctx, vdb, dc, r, action.isDeploymentInReadyState(*dc))
+	if err != nil {
+		log.Info("Canary of ", vdb.ObjectMeta.Name, " version ", vdb.Status.Canary.Version, " held at ",
+			vdb.Status.Canary.Weight, "%, ", err)
+		return nil
+	}
+	if reason != "" {
+		abortCanary(vdb, reason)
+		return nil
+	}
+	return advanceCanary(vdb, now)
+}
+
+func advanceCanary(vdb *v1alpha1.VirtualDatabase, now time.Time) error {
+	steps := canarySteps(vdb)
+	status := vdb.Status.Canary
+	if int(status.Step) >= len(steps) {
+		status.Step = int32(len(steps) - 1)
+	}
+	pause, err := canaryPause(steps[status.Step])
+	if err != nil {
+		return err
+	}
+	if now.Before(status.StepStarted.Add(pause)) {
+		return nil
+	}
+
+	if int(status.Step)+1 < len(steps) {
+		status.Step++
+		status.Weight = steps[status.Step].Weight
+		status.StepStarted = metav1.NewTime(now)
+		log.Info("Canary of ", vdb.ObjectMeta.Name, " version ", status.Version, " moved to ", status.Weight, "%")
+		return nil
+	}
+
+	if err := switchVersion(vdb, status.Version, now); err != nil {
+		return err
+	}
+	log.Info("Canary of ", vdb.ObjectMeta.Name, " version ", status.Version, " promoted")
+	vdb.Status.Canary = nil
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+	return nil
+}
+
+// abortCanary sends all the traffic back to the active version, the Deployment of the new version is removed once
+// running. The active version becomes the current one again so that scaling and pod template changes keep being
+// applied to its Deployment
+func abortCanary(vdb *v1alpha1.VirtualDatabase, reason string) {
+	log.Info("Canary of ", vdb.ObjectMeta.Name, " version ", vdb.Status.Canary.Version, " aborted, ", reason)
+	vdb.Status.Failure = fmt.Sprintf("canary of version %s aborted: %s", vdb.Status.Canary.Version, reason)
+	vdb.Status.Canary = nil
+	vdb.Status.Version = vdb.Status.ActiveVersion
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+}
+
+// canaryFailure returns why the new version is unhealthy, empty when it is healthy. An error means the health is
+// unknown for now, ex: no samples yet, the analysis is retried on the next pass
+func canaryFailure(ctx context.Context, vdb *v1alpha1.VirtualDatabase, dc *appsv1.Deployment, r *ReconcileVirtualDatabase,
+	ready bool) (string, error) {
+
+	if !ready {
+		return "the Deployment is not available", nil
+	}
+
+	pods := &corev1.PodList{}
+	err := r.client.List(ctx, pods, client.InNamespace(dc.Namespace), client.MatchingLabels(dc.Spec.Selector.MatchLabels))
+	if err != nil {
+		return "", fmt.Errorf("failed to list the pods of the canary: %v", err)
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.RestartCount > 0 {
+				return fmt.Sprintf("container %s of pod %s restarted", status.Name, pod.Name), nil
+			}
+		}
+	}
+
+	if vdb.Spec.Rollout.Canary == nil || vdb.Spec.Rollout.Canary.Analysis == nil {
+		return "", nil
+	}
+	analysis := vdb.Spec.Rollout.Canary.Analysis
+	query := analysis.Query
+	if query == "" {
+		query = defaultCanaryQuery
+	}
+	query = strings.NewReplacer(
+		"{{namespace}}", vdb.ObjectMeta.Namespace,
+		"{{service}}", versionDeploymentName(vdb, vdb.Status.Canary.Version),
+		"{{version}}", vdb.Status.Canary.Version,
+	).Replace(query)
+
+	rate, err := prometheusQuery(analysis.PrometheusURL, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to query the error rate of the canary: %v", err)
+	}
+	max, _ := maxErrorRate(analysis)
+	if rate > max {
+		return fmt.Sprintf("error rate %g is above %g", rate, max), nil
+	}
+	return "", nil
+}
+
+// prometheusQuery runs an instant query with the token of the operator, no samples or no traffic is errNoSamples
+func prometheusQuery(prometheusURL string, query string) (float64, error) {
+	tlsConfig := &tls.Config{}
+	if ca, err := ioutil.ReadFile(serviceAccountDir + "/service-ca.crt"); err == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(ca)
+		tlsConfig.RootCAs = pool
+	}
+	httpClient := &http.Client{Timeout: 30 * time.Second, Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(prometheusURL, "/")+"/api/v1/query?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return 0, err
+	}
+	if token, err := ioutil.ReadFile(serviceAccountDir + "/token"); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("prometheus returned %s", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	return parsePrometheusResult(body)
+}
+
+func parsePrometheusResult(body []byte) (float64, error) {
+	var result struct {
+		Status string `json:"status"`
+		Data   struct {
+			Result []struct {
+				Value []interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed with status %s", result.Status)
+	}
+	if len(result.Data.Result) == 0 || len(result.Data.Result[0].Value) != 2 {
+		return 0, errNoSamples
+	}
+	str, ok := result.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected value %v", result.Data.Result[0].Value[1])
+	}
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) {
+		return 0, errNoSamples
+	}
+	return value, nil
+}
+
+// buildCanaryService returns the Service of the version receiving the canary traffic
+func buildCanaryService(service corev1.Service, vdb *v1alpha1.VirtualDatabase) corev1.Service {
+	version := vdb.Status.Canary.Version
+	canary := *service.DeepCopy()
+	canary.ObjectMeta.Name = versionDeploymentName(vdb, version)
+	canary.ObjectMeta.Labels[deploymentVersionLabel] = version
+	delete(canary.ObjectMeta.Labels, "discovery.3scale.net")
+	canary.ObjectMeta.Annotations = map[string]string{}
+	canary.Spec.Selector = copyWith(matchLabels(vdb.ObjectMeta.Name), deploymentVersionLabel, version)
+	return canary
+}
+
+// applyCanaryWeights splits the traffic of the Route between the active version and the canary
+func applyCanaryWeights(vdb *v1alpha1.VirtualDatabase, route *oroutev1.Route) {
+	weight := int32(100)
+	route.Spec.AlternateBackends = nil
+	if canary := vdb.Status.Canary; canary != nil && isCanary(vdb) {
+		weight = 100 - canary.Weight
+		canaryWeight := canary.Weight
+		route.Spec.AlternateBackends = []oroutev1.RouteTargetReference{
+			{Kind: "Service", Name: versionDeploymentName(vdb, canary.Version), Weight: &canaryWeight},
+		}
+	}
+	route.Spec.To.Weight = &weight
+}
+
+// canaryBackendRefs backends of the HTTPRoute, split between the active version and the canary
+func canaryBackendRefs(vdb *v1alpha1.VirtualDatabase, serviceName string, port int32) []interface{} {
+	refs := []interface{}{
+		map[string]interface{}{
+			"name": serviceName,
+			"port": int64(port),
+		},
+	}
+	if canary := vdb.Status.Canary; canary != nil && isCanary(vdb) {
+		refs[0].(map[string]interface{})["weight"] = int64(100 - canary.Weight)
+		refs = append(refs, map[string]interface{}{
+			"name":   versionDeploymentName(vdb, canary.Version),
+			"port":   int64(port),
+			"weight": int64(canary.Weight),
+		})
+	}
+	return refs
+}
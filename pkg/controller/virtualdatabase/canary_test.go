@@ -0,0 +1,162 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func canaryVdb() *v1alpha1.VirtualDatabase {
+	vdb := testVdb()
+	vdb.Spec.Rollout = &v1alpha1.RolloutObject{
+		Strategy: v1alpha1.RolloutCanary,
+		Canary: &v1alpha1.CanaryObject{
+			Steps: []v1alpha1.CanaryStep{{Weight: 10, Pause: "10m"}, {Weight: 50}},
+		},
+	}
+	vdb.Status.Version = "3"
+	vdb.Status.ActiveVersion = "2"
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseDeploying
+	return vdb
+}
+
+func TestCanarySteps(t *testing.T) {
+	vdb := canaryVdb()
+	assert.True(t, isVersioned(vdb))
+	now := time.Now()
+
+	startCanary(vdb, now)
+	assert.Equal(t, "3", vdb.Status.Canary.Version)
+	assert.Equal(t, int32(10), vdb.Status.Canary.Weight)
+
+	// the pause of the first step is not over
+	assert.Nil(t, advanceCanary(vdb, now.Add(9*time.Minute)))
+	assert.Equal(t, int32(0), vdb.Status.Canary.Step)
+
+	assert.Nil(t, advanceCanary(vdb, now.Add(10*time.Minute)))
+	assert.Equal(t, int32(1), vdb.Status.Canary.Step)
+	assert.Equal(t, int32(50), vdb.Status.Canary.Weight)
+
+	// second step uses the default pause, then the version is promoted
+	assert.Nil(t, advanceCanary(vdb, now.Add(14*time.Minute)))
+	assert.Equal(t, int32(1), vdb.Status.Canary.Step)
+	assert.Nil(t, advanceCanary(vdb, now.Add(15*time.Minute)))
+	assert.Nil(t, vdb.Status.Canary)
+	assert.Equal(t, "3", vdb.Status.ActiveVersion)
+	assert.Equal(t, "2", vdb.Status.PreviousVersion)
+	assert.Equal(t, v1alpha1.ReconcilerPhaseRunning, vdb.Status.Phase)
+}
+
+func TestAbortCanary(t *testing.T) {
+	vdb := canaryVdb()
+	startCanary(vdb, time.Now())
+	abortCanary(vdb, "error rate 0.2 is above 0.05")
+
+	assert.Nil(t, vdb.Status.Canary)
+	assert.Equal(t, "2", vdb.Status.ActiveVersion)
+	assert.Equal(t, "canary of version 3 aborted: error rate 0.2 is above 0.05", vdb.Status.Failure)
+	assert.Equal(t, v1alpha1.ReconcilerPhaseRunning, vdb.Status.Phase)
+}
+
+func TestScaleAfterAbortCanary(t *testing.T) {
+	vdb := canaryVdb()
+	replicas := int32(2)
+	vdb.Spec.Replicas = &replicas
+	startCanary(vdb, time.Now())
+	abortCanary(vdb, "the Deployment is not available")
+
+	// the active version is managed again, its Deployment is the one scaled and the canary one is removed
+	assert.Equal(t, vdb.Status.ActiveVersion, vdb.Status.Version)
+	assert.Equal(t, []string{"dv-v2"}, retainedDeployments(vdb, time.Now()))
+
+	dc := &appsv1.Deployment{}
+	dc.Spec.Replicas = &replicas
+	assert.False(t, syncReplicas(vdb, &dc.Spec.Replicas))
+
+	scaled := int32(5)
+	vdb.Spec.Replicas = &scaled
+	assert.True(t, syncReplicas(vdb, &dc.Spec.Replicas))
+	assert.Equal(t, int32(5), *dc.Spec.Replicas)
+
+	// the next build is a newer version than the aborted one
+	vdb.Status.History = []v1alpha1.BuildHistoryEntry{{Version: "2"}, {Version: "3"}}
+	assert.Equal(t, "3", latestVersion(vdb))
+}
+
+func TestCanaryTrafficSplit(t *testing.T) {
+	vdb := canaryVdb()
+	service := buildService(vdb, true)
+
+	route := buildRoute(service, vdb)
+	assert.Equal(t, int32(100), *route.Spec.To.Weight)
+	assert.Nil(t, route.Spec.AlternateBackends)
+	assert.Equal(t, 1, len(canaryBackendRefs(vdb, service.Name, 8080)))
+
+	startCanary(vdb, time.Now())
+	route = buildRoute(service, vdb)
+	assert.Equal(t, "dv", route.Spec.To.Name)
+	assert.Equal(t, int32(90), *route.Spec.To.Weight)
+	assert.Equal(t, "dv-v3", route.Spec.AlternateBackends[0].Name)
+	assert.Equal(t, int32(10), *route.Spec.AlternateBackends[0].Weight)
+
+	refs := canaryBackendRefs(vdb, service.Name, 8080)
+	assert.Equal(t, int64(90), refs[0].(map[string]interface{})["weight"])
+	assert.Equal(t, "dv-v3", refs[1].(map[string]interface{})["name"])
+	assert.Equal(t, int64(10), refs[1].(map[string]interface{})["weight"])
+
+	canary := buildCanaryService(service, vdb)
+	assert.Equal(t, "dv-v3", canary.Name)
+	assert.Equal(t, "3", canary.Spec.Selector[deploymentVersionLabel])
+	assert.Equal(t, "2", service.Spec.Selector[deploymentVersionLabel])
+	assert.Equal(t, 0, len(canary.Annotations))
+}
+
+func TestParsePrometheusResult(t *testing.T) {
+	value, err := parsePrometheusResult([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1600000000.1,"0.125"]}]}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, 0.125, value)
+
+	_, err = parsePrometheusResult([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	assert.Equal(t, errNoSamples, err)
+
+	_, err = parsePrometheusResult([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"value":[1600000000.1,"NaN"]}]}}`))
+	assert.Equal(t, errNoSamples, err)
+
+	_, err = parsePrometheusResult([]byte(`{"status":"error"}`))
+	assert.NotNil(t, err)
+}
+
+func TestValidateCanary(t *testing.T) {
+	vdb := canaryVdb()
+	assert.Nil(t, validateRollout(vdb))
+
+	vdb.Spec.Rollout.Canary.Analysis = &v1alpha1.CanaryAnalysisObject{PrometheusURL: "https://thanos-querier.openshift-monitoring.svc:9091"}
+	assert.Nil(t, validateRollout(vdb))
+
+	vdb.Spec.Rollout.Canary.Analysis.MaxErrorRate = "high"
+	assert.NotNil(t, validateRollout(vdb))
+
+	vdb.Spec.Rollout.Canary.Analysis.MaxErrorRate = "0.01"
+	vdb.Spec.Rollout.Canary.Steps[0].Pause = "soon"
+	assert.NotNil(t, validateRollout(vdb))
+}
@@ -0,0 +1,115 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// connectionFactories returns the source types shipped with the operator merged with the ConnectionFactory
+// resources of the namespace
+func connectionFactories(ctx context.Context, r *ReconcileVirtualDatabase, namespace string) (map[string]conf.ConnectionFactory, error) {
+	list := &v1alpha1.ConnectionFactoryList{}
+	if err := r.client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			// the ConnectionFactory CRD is not installed
+			return constants.ConnectionFactories, nil
+		}
+		return nil, err
+	}
+	return mergeConnectionFactories(constants.ConnectionFactories, list.Items), nil
+}
+
+// mergeConnectionFactories adds the ConnectionFactory resources to the source types, a resource replaces the
+// source type of the same name
+func mergeConnectionFactories(defaults map[string]conf.ConnectionFactory, resources []v1alpha1.ConnectionFactory) map[string]conf.ConnectionFactory {
+	factories := make(map[string]conf.ConnectionFactory, len(defaults)+len(resources))
+	for k, v := range defaults {
+		factories[k] = v
+	}
+	for _, cf := range resources {
+		name := cf.Spec.Name
+		if name == "" {
+			name = cf.ObjectMeta.Name
+		}
+		name = strings.ToLower(name)
+
+		valid := true
+		for _, gav := range cf.Spec.Dependencies {
+			if _, err := maven.ParseGAV(gav); err != nil {
+				log.Warnf("ConnectionFactory %s ignored, invalid dependency %s: %v", cf.ObjectMeta.Name, gav, err)
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		prefix := cf.Spec.SpringBootPropertyPrefix
+		if prefix == "" {
+			prefix = "spring.teiid.data." + name
+		}
+
+		factories[name] = conf.ConnectionFactory{
+			Name:                     name,
+			DriverNames:              cf.Spec.DriverNames,
+			TranslatorName:           cf.Spec.TranslatorName,
+			Dialect:                  cf.Spec.Dialect,
+			SpringBootPropertyPrefix: prefix,
+			JdbcSource:               cf.Spec.JdbcSource,
+			Dependencies:             cf.Spec.Dependencies,
+		}
+	}
+	return factories
+}
+
+// usedConnectionFactories returns the source types the vdb is built with, sorted by name. The sources of a maven
+// VDB are only known once it is fetched, all the source types are returned for it
+func usedConnectionFactories(vdb *v1alpha1.VirtualDatabase, factories map[string]conf.ConnectionFactory) []conf.ConnectionFactory {
+	names := []string{}
+	if vdb.Spec.Build.Source.DDL != "" {
+		for _, source := range vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL) {
+			if _, ok := factories[strings.ToLower(source.Type)]; ok {
+				names = append(names, strings.ToLower(source.Type))
+			}
+		}
+	} else {
+		for name := range factories {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	used := []conf.ConnectionFactory{}
+	for i, name := range names {
+		if i == 0 || names[i-1] != name {
+			used = append(used, factories[name])
+		}
+	}
+	return used
+}
@@ -0,0 +1,136 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func snowflakeConnectionFactory() v1alpha1.ConnectionFactory {
+	return v1alpha1.ConnectionFactory{
+		ObjectMeta: metav1.ObjectMeta{Name: "Snowflake", Namespace: "test"},
+		Spec: v1alpha1.ConnectionFactorySpec{
+			DriverNames:    []string{"net.snowflake.client.jdbc.SnowflakeDriver"},
+			TranslatorName: "snowflake",
+			JdbcSource:     true,
+			Dependencies: []string{
+				"net.snowflake:snowflake-jdbc:3.12.8",
+				"com.example.teiid:spring-data-snowflake:1.0.0",
+			},
+		},
+	}
+}
+
+func TestMergeConnectionFactories(t *testing.T) {
+	postgresql := snowflakeConnectionFactory()
+	postgresql.ObjectMeta.Name = "custom-postgresql"
+	postgresql.Spec.Name = "postgresql"
+	postgresql.Spec.SpringBootPropertyPrefix = "spring.datasource.custom"
+
+	invalid := snowflakeConnectionFactory()
+	invalid.ObjectMeta.Name = "invalid"
+	invalid.Spec.Dependencies = []string{"snowflake-jdbc"}
+
+	factories := mergeConnectionFactories(constants.ConnectionFactories,
+		[]v1alpha1.ConnectionFactory{snowflakeConnectionFactory(), postgresql, invalid})
+
+	assert.Equal(t, len(constants.ConnectionFactories)+1, len(factories))
+	assert.Equal(t, "snowflake", factories["snowflake"].Name)
+	assert.Equal(t, "spring.teiid.data.snowflake", factories["snowflake"].SpringBootPropertyPrefix)
+	assert.Equal(t, 2, len(factories["snowflake"].Dependencies))
+	assert.Equal(t, "spring.datasource.custom", factories["postgresql"].SpringBootPropertyPrefix)
+	_, ok := factories["invalid"]
+	assert.False(t, ok)
+
+	// the source types of the operator are left alone
+	assert.Equal(t, 0, len(constants.ConnectionFactories["postgresql"].Dependencies))
+	_, ok = constants.ConnectionFactories["snowflake"]
+	assert.False(t, ok)
+}
+
+func TestConnectionFactoryInBuild(t *testing.T) {
+	vdb := &v1alpha1.VirtualDatabase{}
+	vdb.ObjectMeta.Name = "inventory"
+	vdb.Spec.Build.Source.DDL = `CREATE DATABASE inventory;
+	USE DATABASE inventory;
+	CREATE SERVER inventorydb FOREIGN DATA WRAPPER snowflake;
+	CREATE SCHEMA stock SERVER inventorydb;`
+	vdb.Spec.DataSources = []v1alpha1.DataSourceObject{{
+		Name: "inventorydb",
+		Type: "snowflake",
+		Properties: []corev1.EnvVar{
+			{Name: "jdbc-url", Value: "jdbc:snowflake://account.snowflakecomputing.com/?db=inventory"},
+		},
+	}}
+	sources := vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL)
+	factories := mergeConnectionFactories(constants.ConnectionFactories, []v1alpha1.ConnectionFactory{snowflakeConnectionFactory()})
+
+	project, err := GenerateVdbPom(vdb, sources, factories, false, false, false)
+	assert.Nil(t, err)
+	assert.True(t, hasDependency(project, "net.snowflake", "snowflake-jdbc"))
+	assert.True(t, hasDependency(project, "com.example.teiid", "spring-data-snowflake"))
+	assert.False(t, hasDependency(project, "org.teiid", "spring-data-snowflake"))
+
+	envs, err := convert2SpringProperties(vdb.Spec.DataSources, sources, factories)
+	assert.Nil(t, err)
+	assert.Equal(t, "SPRING_TEIID_DATA_SNOWFLAKE_INVENTORYDB_JDBC_URL", envs[0].Name)
+}
+
+func TestConnectionFactoryDigest(t *testing.T) {
+	vdb := &v1alpha1.VirtualDatabase{}
+	vdb.Spec.Build.Source.DDL = `CREATE DATABASE inventory;
+	USE DATABASE inventory;
+	CREATE SERVER inventorydb FOREIGN DATA WRAPPER snowflake;
+	CREATE SERVER accountsdb FOREIGN DATA WRAPPER postgresql;`
+	snowflake := snowflakeConnectionFactory()
+	factories := mergeConnectionFactories(constants.ConnectionFactories, []v1alpha1.ConnectionFactory{snowflake})
+
+	used := usedConnectionFactories(vdb, factories)
+	assert.Equal(t, 2, len(used))
+	assert.Equal(t, "postgresql", used[0].Name)
+	assert.Equal(t, "snowflake", used[1].Name)
+	digest, err := ComputeForVirtualDatabase(vdb, factories)
+	assert.Nil(t, err)
+
+	// a changed source type the vdb uses changes the digest
+	snowflake.Spec.SpringBootPropertyPrefix = "spring.datasource.snowflake"
+	changed, err := ComputeForVirtualDatabase(vdb, mergeConnectionFactories(constants.ConnectionFactories,
+		[]v1alpha1.ConnectionFactory{snowflake}))
+	assert.Nil(t, err)
+	assert.NotEqual(t, digest, changed)
+
+	// others do not
+	other := snowflakeConnectionFactory()
+	other.ObjectMeta.Name = "other"
+	unchanged, err := ComputeForVirtualDatabase(vdb, mergeConnectionFactories(constants.ConnectionFactories,
+		[]v1alpha1.ConnectionFactory{snowflakeConnectionFactory(), other}))
+	assert.Nil(t, err)
+	assert.Equal(t, digest, unchanged)
+
+	// the sources of a maven vdb are not known, any change counts
+	vdb.Spec.Build.Source.DDL = ""
+	vdb.Spec.Build.Source.Maven = "org.teiid:dv-customer:1.0"
+	assert.Equal(t, len(factories), len(usedConnectionFactories(vdb, factories)))
+}
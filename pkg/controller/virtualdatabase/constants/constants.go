@@ -1,6 +1,8 @@
 package constants
 
 import (
+	"fmt"
+
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	"github.com/teiid/teiid-operator/pkg/util/conf"
 	corev1 "k8s.io/api/core/v1"
@@ -50,20 +52,84 @@ func GetMavenRepositories(vdb *v1alpha1.VirtualDatabase) map[string]string {
 	return repos
 }
 
-// GetComputingResources --
-func GetComputingResources(vdb *v1alpha1.VirtualDatabase) corev1.ResourceRequirements {
-	// resources for the container
-	if &vdb.Spec.Resources == nil {
-		return corev1.ResourceRequirements{
-			Limits: corev1.ResourceList{
-				"memory": resource.MustParse("512Mi"),
-				"cpu":    resource.MustParse("1.0"),
-			},
-			Requests: corev1.ResourceList{
-				"memory": resource.MustParse("256Mi"),
-				"cpu":    resource.MustParse("0.2"),
-			},
+// GetComputingResources returns the requests and limits of the Virtual Database container, the defaults of the
+// policy fill in what is not given and the result must stay within the minimum and maximum of the policy
+func GetComputingResources(vdb *v1alpha1.VirtualDatabase, policy conf.ResourcePolicy) (corev1.ResourceRequirements, error) {
+	resources := vdb.Spec.Resources.DeepCopy()
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	if resources.Limits == nil {
+		resources.Limits = corev1.ResourceList{}
+	}
+
+	defaultLimits, err := parseResourceList(policy.DefaultLimits)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	for name, limit := range defaultLimits {
+		if _, ok := resources.Limits[name]; ok {
+			continue
+		}
+		// a request above the default limit raises the limit
+		if request, ok := resources.Requests[name]; ok && request.Cmp(limit) > 0 {
+			limit = request
+		}
+		resources.Limits[name] = limit
+	}
+
+	defaultRequests, err := parseResourceList(policy.DefaultRequests)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	for name, request := range defaultRequests {
+		if _, ok := resources.Requests[name]; ok {
+			continue
+		}
+		// a limit below the default request lowers the request
+		if limit, ok := resources.Limits[name]; ok && limit.Cmp(request) < 0 {
+			request = limit
+		}
+		resources.Requests[name] = request
+	}
+
+	min, err := parseResourceList(policy.Min)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	max, err := parseResourceList(policy.Max)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	for _, list := range []corev1.ResourceList{resources.Requests, resources.Limits} {
+		for name, value := range list {
+			if bound, ok := min[name]; ok && value.Cmp(bound) < 0 {
+				return corev1.ResourceRequirements{}, fmt.Errorf("%s of %s is below the minimum %s of the resource policy",
+					name, value.String(), bound.String())
+			}
+			if bound, ok := max[name]; ok && value.Cmp(bound) > 0 {
+				return corev1.ResourceRequirements{}, fmt.Errorf("%s of %s is above the maximum %s of the resource policy",
+					name, value.String(), bound.String())
+			}
+		}
+	}
+	for name, request := range resources.Requests {
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return corev1.ResourceRequirements{}, fmt.Errorf("%s request of %s is above its limit of %s", name,
+				request.String(), limit.String())
+		}
+	}
+	return *resources, nil
+}
+
+func parseResourceList(values map[string]string) (corev1.ResourceList, error) {
+	list := corev1.ResourceList{}
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of %q in the resource policy: %v", name, value, err)
 		}
+		list[corev1.ResourceName(name)] = quantity
 	}
-	return vdb.Spec.Resources
+	return list, nil
 }
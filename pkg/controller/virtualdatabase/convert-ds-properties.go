@@ -22,7 +22,7 @@ import (
 	"strings"
 	"unicode"
 
-	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
 	"github.com/teiid/teiid-operator/pkg/util/envvar"
 	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
 	corev1 "k8s.io/api/core/v1"
@@ -30,7 +30,8 @@ import (
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 )
 
-func convert2SpringProperties(sourcesConfigured []v1alpha1.DataSourceObject, sourcesFromDdl []vdbutil.DatasourceInfo) ([]corev1.EnvVar, error) {
+func convert2SpringProperties(sourcesConfigured []v1alpha1.DataSourceObject, sourcesFromDdl []vdbutil.DatasourceInfo,
+	factories map[string]conf.ConnectionFactory) ([]corev1.EnvVar, error) {
 	envs := make([]corev1.EnvVar, 0)
 
 	for _, source := range sourcesFromDdl {
@@ -48,7 +49,7 @@ func convert2SpringProperties(sourcesConfigured []v1alpha1.DataSourceObject, sou
 			return nil, errors.New("Configured Datasource " + configuredSource.Name + " has spaces, which is not allowed")
 		}
 
-		if c, ok := constants.ConnectionFactories[strings.ToLower(source.Type)]; ok {
+		if c, ok := factories[strings.ToLower(source.Type)]; ok {
 			prefix = strings.ToLower(c.SpringBootPropertyPrefix)
 		} else {
 			// Custom translators must map to this property prefix
@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
 	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -81,7 +82,7 @@ func TestSpringProperties(t *testing.T) {
 			Type: "infinispan-hotrod",
 		},
 	}
-	envs, err := convert2SpringProperties(datasources, sourcesFromDdl)
+	envs, err := convert2SpringProperties(datasources, sourcesFromDdl, constants.ConnectionFactories)
 	assert.NotNil(t, envs)
 	assert.Nil(t, err)
 
@@ -167,7 +168,7 @@ func TestSoapProperties(t *testing.T) {
 			Type: "soap",
 		},
 	}
-	envs, err := convert2SpringProperties(datasources, sourcesFromDdl)
+	envs, err := convert2SpringProperties(datasources, sourcesFromDdl, constants.ConnectionFactories)
 	assert.NotNil(t, envs)
 	assert.Nil(t, err)
 
@@ -205,7 +206,7 @@ func TestCustom(t *testing.T) {
 		},
 	}
 
-	envs, err := convert2SpringProperties(datasources, sourcesFromDdl)
+	envs, err := convert2SpringProperties(datasources, sourcesFromDdl, constants.ConnectionFactories)
 	assert.NotNil(t, envs)
 	assert.Nil(t, err)
 
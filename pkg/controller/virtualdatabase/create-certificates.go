@@ -76,7 +76,19 @@ func (action *createCertificateAction) Handle(ctx context.Context, vdb *v1alpha1
 		log.Error("Failed to read /var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt")
 		return err
 	}
-	truststorePkcs12, err := pkcs12.CreatePkcs12Truststore(constants.KeystorePassword, defaultTrustCert)
+	trustCerts := [][]byte{defaultTrustCert}
+
+	// trust the identity provider, when it is not signed by the cluster CA
+	oidcCert, err := oidcCACertificate(ctx, r.client, vdb)
+	if err != nil {
+		log.Error("Failed to read the CA certificate of the identity provider")
+		return err
+	}
+	if oidcCert != nil {
+		trustCerts = append(trustCerts, oidcCert)
+	}
+
+	truststorePkcs12, err := pkcs12.CreatePkcs12Truststore(constants.KeystorePassword, trustCerts...)
 	if err != nil {
 		log.Error("Failed to create the Truststore")
 		return err
@@ -0,0 +1,232 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const gatewayGroup = "gateway.networking.k8s.io"
+
+// gatewayVersion returns the preferred version of the Gateway API in which the kind is served, empty when
+// the Gateway API is not installed on the cluster
+func gatewayVersion(r *ReconcileVirtualDatabase, kind string) string {
+	for _, version := range []string{"v1", "v1beta1", "v1alpha2"} {
+		if kubernetes.HasServerResource(r.client, gatewayGroup+"/"+version, kind) {
+			return version
+		}
+	}
+	return ""
+}
+
+func hasGatewayAPI(r *ReconcileVirtualDatabase) bool {
+	return gatewayVersion(r, "HTTPRoute") != ""
+}
+
+// ensureGatewayRoutes creates or updates HTTPRoute to the odata service and when supported by the cluster a TLSRoute
+// to the secure jdbc port, the routes are recorded in desired
+func (action *createServiceAction) ensureGatewayRoutes(ctx context.Context, service corev1.Service, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase, desired exposedObjects) error {
+
+	options := exposeOptions(vdb)
+	if options.Gateway == nil || options.Gateway.Name == "" {
+		return errors.New("gateway must be configured in exposeOptions to expose through Gateway")
+	}
+
+	version := gatewayVersion(r, "HTTPRoute")
+	if version == "" {
+		return errors.New("Gateway API is not available on the cluster")
+	}
+
+	httpRoute := buildGatewayRoute(service, vdb, "HTTPRoute", version, service.Name, options.Gateway.SectionName, options.Host, odataPort(vdb))
+	if err := ensureUnstructured(ctx, vdb, httpRoute, r); err != nil {
+		return err
+	}
+	desired.add("HTTPRoute", httpRoute.GetName())
+
+	// jdbc is routed on SNI, needs a host name to route on
+	if tlsVersion := gatewayVersion(r, "TLSRoute"); tlsVersion != "" && options.Host != "" {
+		for _, port := range passthroughPorts(vdb) {
+			name := service.Name + "-" + port.Name
+			host := protocolHost(port.Name, options.Host)
+			tlsRoute := buildGatewayRoute(service, vdb, "TLSRoute", tlsVersion, name, options.Gateway.TLSSectionName, host, getExposedPort(port))
+			if err := ensureUnstructured(ctx, vdb, tlsRoute, r); err != nil {
+				return err
+			}
+			desired.add("TLSRoute", tlsRoute.GetName())
+		}
+	}
+	return nil
+}
+
+func buildGatewayRoute(service corev1.Service, vdb *v1alpha1.VirtualDatabase, kind string, version string, name string,
+	sectionName string, host string, port int32) *unstructured.Unstructured {
+
+	options := exposeOptions(vdb)
+
+	labels := map[string]interface{}{}
+	for k, v := range service.ObjectMeta.Labels {
+		labels[k] = v
+	}
+	if kind == "HTTPRoute" {
+		labels["teiid.io/api"] = "odata"
+	}
+	annotations := map[string]interface{}{}
+	for k, v := range options.Annotations {
+		annotations[k] = v
+	}
+
+	parentRef := map[string]interface{}{
+		"name": options.Gateway.Name,
+	}
+	if options.Gateway.Namespace != "" {
+		parentRef["namespace"] = options.Gateway.Namespace
+	}
+	if sectionName != "" {
+		parentRef["sectionName"] = sectionName
+	}
+
+	backendRefs := []interface{}{
+		map[string]interface{}{
+			"name": service.Name,
+			"port": int64(port),
+		},
+	}
+	if kind == "HTTPRoute" {
+		backendRefs = canaryBackendRefs(vdb, service.Name, port)
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": backendRefs,
+			},
+		},
+	}
+	if host != "" {
+		spec["hostnames"] = []interface{}{host}
+	}
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":        name,
+				"namespace":   vdb.ObjectMeta.Namespace,
+				"labels":      labels,
+				"annotations": annotations,
+			},
+			"spec": spec,
+		},
+	}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: gatewayGroup, Version: version, Kind: kind})
+	return obj
+}
+
+// ensureUnstructured creates the object or updates the labels, annotations and spec of the existing one
+func ensureUnstructured(ctx context.Context, vdb *v1alpha1.VirtualDatabase, desired *unstructured.Unstructured, r *ReconcileVirtualDatabase) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(desired.GroupVersionKind())
+	obj.SetName(desired.GetName())
+	obj.SetNamespace(desired.GetNamespace())
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, obj, func() error {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range desired.GetLabels() {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range desired.GetAnnotations() {
+			annotations[k] = v
+		}
+		obj.SetAnnotations(annotations)
+		obj.Object["spec"] = desired.Object["spec"]
+		return controllerutil.SetControllerReference(vdb, obj, r.client.GetScheme())
+	})
+	return err
+}
+
+// gatewayURL returns the URL of the odata service, when no host is configured the hostname of the listener or
+// the address of the Gateway is used
+func gatewayURL(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (string, error) {
+	options := exposeOptions(vdb)
+	namespace := options.Gateway.Namespace
+	if namespace == "" {
+		namespace = vdb.ObjectMeta.Namespace
+	}
+
+	version := gatewayVersion(r, "Gateway")
+	if version == "" {
+		return "", errors.New("Gateway kind of the Gateway API is not served by the cluster")
+	}
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(schema.GroupVersionKind{Group: gatewayGroup, Version: version, Kind: "Gateway"})
+	if err := r.client.Get(ctx, types.NamespacedName{Name: options.Gateway.Name, Namespace: namespace}, gateway); err != nil {
+		return "", err
+	}
+
+	scheme := "http"
+	host := options.Host
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	for _, l := range listeners {
+		listener, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if options.Gateway.SectionName != "" && listener["name"] != options.Gateway.SectionName {
+			continue
+		}
+		if protocol, _ := listener["protocol"].(string); protocol == "HTTPS" {
+			scheme = "https"
+		}
+		if hostname, _ := listener["hostname"].(string); host == "" && hostname != "" && !strings.HasPrefix(hostname, "*") {
+			host = hostname
+		}
+		break
+	}
+
+	if host == "" {
+		addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+		if len(addresses) > 0 {
+			if address, ok := addresses[0].(map[string]interface{}); ok {
+				host, _ = address["value"].(string)
+			}
+		}
+	}
+	if host == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("%s://%s/odata", scheme, host), nil
+}
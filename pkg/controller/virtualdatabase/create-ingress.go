@@ -0,0 +1,139 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ensureIngress creates or updates the Ingress to the odata service, returns the name of the Ingress
+func (action *createServiceAction) ensureIngress(ctx context.Context, service corev1.Service, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) (string, error) {
+
+	desired := buildIngress(service, vdb)
+	if kubernetes.HasServerResource(r.client, networkingv1beta1.SchemeGroupVersion.String(), "Ingress") {
+		ingress := &networkingv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+		_, err := controllerutil.CreateOrUpdate(ctx, r.client, ingress, func() error {
+			mergeMetadata(&ingress.ObjectMeta, desired.ObjectMeta)
+			ingress.Spec = desired.Spec
+			return controllerutil.SetControllerReference(vdb, ingress, r.client.GetScheme())
+		})
+		return desired.Name, err
+	}
+
+	// older clusters only serve the extensions group, the Ingress type is same
+	extDesired := extv1beta1.Ingress{}
+	if err := convertIngress(&desired, &extDesired); err != nil {
+		return "", err
+	}
+	ingress := &extv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, ingress, func() error {
+		mergeMetadata(&ingress.ObjectMeta, extDesired.ObjectMeta)
+		ingress.Spec = extDesired.Spec
+		return controllerutil.SetControllerReference(vdb, ingress, r.client.GetScheme())
+	})
+	return desired.Name, err
+}
+
+func buildIngress(service corev1.Service, vdb *v1alpha1.VirtualDatabase) networkingv1beta1.Ingress {
+	options := exposeOptions(vdb)
+
+	metadata := service.ObjectMeta.DeepCopy()
+	metadata.Labels["teiid.io/api"] = "odata"
+	metadata.Annotations = map[string]string{}
+	if options.IngressClassName != "" {
+		// ingressClassName field is not available in this API version
+		metadata.Annotations["kubernetes.io/ingress.class"] = options.IngressClassName
+	}
+	for k, v := range options.Annotations {
+		metadata.Annotations[k] = v
+	}
+
+	ingress := networkingv1beta1.Ingress{
+		ObjectMeta: *metadata,
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: options.Host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: service.Name,
+										ServicePort: intstr.FromInt(int(odataPort(vdb))),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if options.TLSSecretName != "" {
+		tls := networkingv1beta1.IngressTLS{SecretName: options.TLSSecretName}
+		if options.Host != "" {
+			tls.Hosts = []string{options.Host}
+		}
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{tls}
+	}
+	ingress.SetGroupVersionKind(networkingv1beta1.SchemeGroupVersion.WithKind("Ingress"))
+	return ingress
+}
+
+func convertIngress(from *networkingv1beta1.Ingress, to *extv1beta1.Ingress) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// ingressURL returns the URL of the odata service, when no host is configured the address assigned by
+// the ingress controller is used
+func ingressURL(vdb *v1alpha1.VirtualDatabase, lb corev1.LoadBalancerStatus) string {
+	options := exposeOptions(vdb)
+	scheme := "http"
+	if options.TLSSecretName != "" {
+		scheme = "https"
+	}
+	host := options.Host
+	if host == "" && len(lb.Ingress) > 0 {
+		host = lb.Ingress[0].Hostname
+		if host == "" {
+			host = lb.Ingress[0].IP
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/odata", scheme, host)
+}
@@ -0,0 +1,129 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	oroutev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testService(vdb *v1alpha1.VirtualDatabase) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        vdb.ObjectMeta.Name,
+			Namespace:   vdb.ObjectMeta.Namespace,
+			Labels:      map[string]string{"app": vdb.ObjectMeta.Name},
+			Annotations: map[string]string{"discovery.3scale.net/port": "8080"},
+		},
+	}
+}
+
+func TestBuildIngress(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.ExposeOptions = &v1alpha1.ExposeOptionsObject{
+		Host:             "dv.example.com",
+		IngressClassName: "nginx",
+		TLSSecretName:    "dv-tls",
+		Annotations:      map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "true"},
+	}
+
+	ingress := buildIngress(testService(vdb), vdb)
+	assert.Equal(t, "dv", ingress.Name)
+	assert.Equal(t, "odata", ingress.Labels["teiid.io/api"])
+	assert.Equal(t, "nginx", ingress.Annotations["kubernetes.io/ingress.class"])
+	assert.Equal(t, "true", ingress.Annotations["nginx.ingress.kubernetes.io/ssl-redirect"])
+	assert.Equal(t, "", ingress.Annotations["discovery.3scale.net/port"])
+	assert.Equal(t, "dv.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "dv", ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+	assert.Equal(t, 8080, ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServicePort.IntValue())
+	assert.Equal(t, "dv-tls", ingress.Spec.TLS[0].SecretName)
+	assert.Equal(t, []string{"dv.example.com"}, ingress.Spec.TLS[0].Hosts)
+
+	assert.Equal(t, "https://dv.example.com/odata", ingressURL(vdb, corev1.LoadBalancerStatus{}))
+
+	vdb.Spec.ExposeOptions = nil
+	assert.Equal(t, "", ingressURL(vdb, corev1.LoadBalancerStatus{}))
+	lb := corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}}
+	assert.Equal(t, "http://10.0.0.1/odata", ingressURL(vdb, lb))
+}
+
+func TestBuildGatewayRoute(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.ExposeOptions = &v1alpha1.ExposeOptionsObject{
+		Host: "dv.example.com",
+		Gateway: &v1alpha1.GatewayReference{
+			Name:           "public",
+			Namespace:      "gateways",
+			TLSSectionName: "passthrough",
+		},
+	}
+
+	route := buildGatewayRoute(testService(vdb), vdb, "TLSRoute", "v1alpha2", "dv-teiid-secure", "passthrough",
+		protocolHost("teiid-secure", "dv.example.com"), 31443)
+	assert.Equal(t, "gateway.networking.k8s.io/v1alpha2", route.GetAPIVersion())
+	assert.Equal(t, "TLSRoute", route.GetKind())
+	assert.Equal(t, "dv-teiid-secure", route.GetName())
+
+	hosts, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	assert.Equal(t, []string{"jdbc-dv.example.com"}, hosts)
+
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	parent := parents[0].(map[string]interface{})
+	assert.Equal(t, "public", parent["name"])
+	assert.Equal(t, "gateways", parent["namespace"])
+	assert.Equal(t, "passthrough", parent["sectionName"])
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	backend := rules[0].(map[string]interface{})["backendRefs"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "dv", backend["name"])
+	assert.Equal(t, int64(31443), backend["port"])
+}
+
+func TestBuildPassthroughRoute(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.ExposeOptions = &v1alpha1.ExposeOptionsObject{Host: "dv.example.com"}
+
+	ports := containerPorts(vdb, true)
+	route := buildPassthroughRoute(testService(vdb), vdb, ports[0])
+	assert.Equal(t, "dv-teiid-secure", route.Name)
+	assert.Equal(t, "jdbc-dv.example.com", route.Spec.Host)
+	assert.Equal(t, "teiid-secure", route.Spec.Port.TargetPort.String())
+	assert.Equal(t, oroutev1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+	assert.Equal(t, "", route.Annotations["discovery.3scale.net/port"])
+
+	vdb.Spec.ExposeOptions = nil
+	route = buildPassthroughRoute(testService(vdb), vdb, ports[0])
+	assert.Equal(t, "", route.Spec.Host)
+}
+
+func TestPassthroughPorts(t *testing.T) {
+	vdb := testVdb()
+	ports := passthroughPorts(vdb)
+	assert.Equal(t, 1, len(ports))
+	assert.Equal(t, "teiid-secure", ports[0].Name)
+
+	secure := false
+	vdb.Spec.Protocols = &v1alpha1.ProtocolsObject{JDBC: &v1alpha1.ProtocolObject{Secure: &secure}}
+	assert.Equal(t, 0, len(passthroughPorts(vdb)))
+}
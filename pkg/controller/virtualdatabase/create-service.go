@@ -14,13 +14,13 @@ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 See the License for the specific language governing permissions and
 limitations under the License.
 */
-
 package virtualdatabase
 
 import (
 	"context"
 	"fmt"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/teiid/teiid-operator/pkg/util/openshift"
 
@@ -28,10 +28,16 @@ import (
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
 	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -51,10 +57,18 @@ func (action *createServiceAction) Name() string {
 
 // CanHandle tells whether this action can handle the virtualdatabase
 func (action *createServiceAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bool {
-	return vdb.Status.Phase == v1alpha1.ReconcilerPhaseServiceImageFinished
+	return vdb.Status.Phase == v1alpha1.ReconcilerPhaseServiceImageFinished ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseServiceCreated ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseKeystoreCreated ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseRunning
 }
 
-// Handle handles the virtualdatabase
+// exposureFailure prefix of the failure set when the vdb could not be exposed, cleared once it is
+const exposureFailure = "Failed to expose the Virtual Database: "
+
+// Handle handles the virtualdatabase, the exposure is reconciled on every pass such that changes to
+// spec.expose are reflected in the Services, Routes, Ingresses and Gateway routes
 func (action *createServiceAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
 	// check to see if the user configured a secret with certificates
 	// if not have generate self signed
@@ -67,63 +81,325 @@ func (action *createServiceAction) Handle(ctx context.Context, vdb *v1alpha1.Vir
 	// Create the service and route needed. We are creating service
 	// before so that we can use the service annotation to create certificate
 	// that can be loaded in a deployment
-	_, err = kubernetes.GetService(ctx, r.client, vdb.ObjectMeta.Name, vdb.ObjectMeta.Namespace)
+	service, err := action.ensureService(ctx, vdb, r, hasCertSecret)
 	if err != nil {
-		service, err := action.createService(vdb, r, hasCertSecret)
-		if err != nil {
-			vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
-			vdb.Status.Failure = "Failed to create Service"
-		} else {
-			log.Info("Services created:" + vdb.ObjectMeta.Name)
-			// create services that need to be exposed
-			var createRoute bool = true
-			for _, exposeType := range vdb.Spec.Expose {
-				if exposeType == v1alpha1.LoadBalancer {
-					_, err := action.createExternalService(vdb, r, vdb.ObjectMeta.Name+"-external", corev1.ServiceTypeLoadBalancer)
-					if err != nil {
-						vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
-						vdb.Status.Failure = "Failed to create External LoadBalancer Service"
-					}
-				} else if exposeType == v1alpha1.NodePort {
-					_, err := action.createExternalService(vdb, r, vdb.ObjectMeta.Name+"-external", corev1.ServiceTypeNodePort)
-					if err != nil {
-						vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
-						vdb.Status.Failure = "Failed to create External NodePort Service"
-					}
-				} else if exposeType == v1alpha1.ExposeVia3scale {
-					log.Info("creation of Route skipped as it is configured to be exposed through 3scale")
-					createRoute = false
-				} else if exposeType == v1alpha1.Route {
-					createRoute = true
-				}
-			}
+		vdb.Status.Failure = "Failed to create Service"
+		return err
+	}
+
+	exposureErr := action.reconcileExposure(ctx, service, vdb, r)
+	if exposureErr != nil {
+		log.Error("Failed to expose the services. ", exposureErr)
+	}
+	setExposureFailure(vdb, exposureErr)
+
+	// publish the endpoints of services created
+	if err := refreshEndpoints(ctx, vdb, r); err != nil {
+		log.Warn("Failed to read the endpoints of the services. ", err)
+	}
 
+	if vdb.Status.Phase == v1alpha1.ReconcilerPhaseServiceImageFinished {
+		log.Info("Services created:" + vdb.ObjectMeta.Name)
+		vdb.Status.Phase = v1alpha1.ReconcilerPhaseServiceCreated
+	}
+	// the deployment goes ahead without the failed exposure, which is retried
+	return exposureErr
+}
+
+// setExposureFailure reports the failure to expose the vdb, or clears the one reported earlier
+func setExposureFailure(vdb *v1alpha1.VirtualDatabase, err error) {
+	if err != nil {
+		vdb.Status.Failure = exposureFailure + err.Error()
+	} else if strings.HasPrefix(vdb.Status.Failure, exposureFailure) {
+		vdb.Status.Failure = ""
+	}
+}
+
+// reconcileExposure creates or updates the objects that expose the vdb as defined in spec.expose and removes
+// the ones that are no longer requested
+func (action *createServiceAction) reconcileExposure(ctx context.Context, service corev1.Service, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) error {
+
+	desired := exposedObjects{}
+	desired.add("Service", service.Name)
+
+	if vdb.Status.Canary != nil && isCanary(vdb) {
+		canary := buildCanaryService(service, vdb)
+		if err := action.ensureExposedService(ctx, vdb, r, canary); err != nil {
+			return fmt.Errorf("Failed to create the canary Service, %s", err)
+		}
+		desired.add("Service", canary.Name)
+	}
+
+	for _, exposeType := range exposeTypes(vdb, r) {
+		switch exposeType {
+		case v1alpha1.LoadBalancer, v1alpha1.NodePort:
+			if desired.has("Service", service.Name+"-external") {
+				log.Warn("Only one of LoadBalancer or NodePort can be exposed, ignoring ", exposeType)
+				continue
+			}
+			serviceType := corev1.ServiceTypeLoadBalancer
+			if exposeType == v1alpha1.NodePort {
+				serviceType = corev1.ServiceTypeNodePort
+			}
+			external := buildExternalService(vdb, service.Name+"-external", serviceType)
+			if err := action.ensureExposedService(ctx, vdb, r, external); err != nil {
+				return fmt.Errorf("Failed to create External %s Service, %s", serviceType, err)
+			}
+			desired.add("Service", external.Name)
+		case v1alpha1.Route:
 			// create route to the odata service
-			if createRoute {
-				route, err := action.createRoute(service, vdb, r)
+			route, err := action.ensureRoute(ctx, buildRoute(service, vdb), vdb, r)
+			if err != nil {
+				return fmt.Errorf("Failed to create route, %s", err)
+			}
+			desired.add("Route", route.Name)
+			if err := openshift.ConsoleLinkExists(); err == nil {
+				openshift.CreateConsoleLink(ctx, &route, r.client, vdb)
+			}
+		case v1alpha1.PassthroughRoute:
+			for _, port := range passthroughPorts(vdb) {
+				route, err := action.ensureRoute(ctx, buildPassthroughRoute(service, vdb, port), vdb, r)
 				if err != nil {
-					vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
-					vdb.Status.Failure = "Failed to create route"
-				} else {
-					log.Info("Route created:" + vdb.ObjectMeta.Name)
-					vdb.Status.Route = fmt.Sprintf("https://%s/odata", route.Spec.Host)
-
-					if err := openshift.ConsoleLinkExists(); err == nil {
-						openshift.CreateConsoleLink(ctx, &route, r.client, vdb)
-					}
+					return fmt.Errorf("Failed to create passthrough routes, %s", err)
 				}
+				desired.add("Route", route.Name)
+			}
+		case v1alpha1.Ingress:
+			name, err := action.ensureIngress(ctx, service, vdb, r)
+			if err != nil {
+				return fmt.Errorf("Failed to create Ingress, %s", err)
+			}
+			desired.add("Ingress", name)
+		case v1alpha1.Gateway:
+			if err := action.ensureGatewayRoutes(ctx, service, vdb, r, desired); err != nil {
+				return fmt.Errorf("Failed to create Gateway routes, %s", err)
 			}
 		}
 	}
-	vdb.Status.Phase = v1alpha1.ReconcilerPhaseServiceCreated
+
+	return action.removeExposedObjects(ctx, vdb, r, desired)
+}
+
+// removeExposedObjects deletes the Services, Routes, Ingresses and Gateway routes owned by the vdb that are not
+// in the desired set, kinds not served by the cluster are skipped
+func (action *createServiceAction) removeExposedObjects(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase, desired exposedObjects) error {
+
+	opts := []client.ListOption{
+		client.InNamespace(vdb.ObjectMeta.Namespace),
+		client.MatchingLabels{"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name},
+	}
+
+	candidates := []runtime.Object{}
+	services := &corev1.ServiceList{}
+	if err := r.client.List(ctx, services, opts...); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		candidates = append(candidates, &services.Items[i])
+	}
+
+	if hasRouteAPI(r) {
+		routes := &oroutev1.RouteList{}
+		if err := r.client.List(ctx, routes, opts...); err != nil {
+			return err
+		}
+		for i := range routes.Items {
+			if routes.Items[i].Name == vdb.ObjectMeta.Name && !desired.has("Route", routes.Items[i].Name) {
+				openshift.RemoveConsoleLink(ctx, r.client, vdb)
+			}
+			candidates = append(candidates, &routes.Items[i])
+		}
+	}
+
+	ingresses := &networkingv1beta1.IngressList{}
+	if err := r.client.List(ctx, ingresses, opts...); err == nil {
+		for i := range ingresses.Items {
+			candidates = append(candidates, &ingresses.Items[i])
+		}
+	} else if meta.IsNoMatchError(err) {
+		extIngresses := &extv1beta1.IngressList{}
+		if err := r.client.List(ctx, extIngresses, opts...); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+		for i := range extIngresses.Items {
+			candidates = append(candidates, &extIngresses.Items[i])
+		}
+	} else {
+		return err
+	}
+
+	for _, kind := range []string{"HTTPRoute", "TLSRoute"} {
+		version := gatewayVersion(r, kind)
+		if version == "" {
+			continue
+		}
+		routes := &unstructured.UnstructuredList{}
+		routes.SetGroupVersionKind(schema.GroupVersionKind{Group: gatewayGroup, Version: version, Kind: kind + "List"})
+		if err := r.client.List(ctx, routes, opts...); err != nil {
+			return err
+		}
+		for i := range routes.Items {
+			routes.Items[i].SetGroupVersionKind(schema.GroupVersionKind{Group: gatewayGroup, Version: version, Kind: kind})
+			candidates = append(candidates, &routes.Items[i])
+		}
+	}
+
+	for _, obj := range candidates {
+		if !isStaleExposedObject(obj, vdb, desired) {
+			continue
+		}
+		if err := r.client.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Removed ", obj.GetObjectKind().GroupVersionKind().Kind, " ", obj.(metav1.Object).GetName(),
+			" no longer exposed by ", vdb.ObjectMeta.Name)
+	}
 	return nil
 }
 
-func (action *createServiceAction) createService(vdb *v1alpha1.VirtualDatabase,
-	r *ReconcileVirtualDatabase, hasCertSecret bool) (corev1.Service, error) {
+// isStaleExposedObject tells whether the object is owned by the vdb and no longer part of its exposure
+func isStaleExposedObject(obj runtime.Object, vdb *v1alpha1.VirtualDatabase, desired exposedObjects) bool {
+	m, ok := obj.(metav1.Object)
+	if !ok || !metav1.IsControlledBy(m, vdb) {
+		return false
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		switch obj.(type) {
+		case *corev1.Service:
+			kind = "Service"
+		case *oroutev1.Route:
+			kind = "Route"
+		case *networkingv1beta1.Ingress, *extv1beta1.Ingress:
+			kind = "Ingress"
+		}
+	}
+	return !desired.has(kind, m.GetName())
+}
+
+// exposedObjects set of the exposed objects by kind and name
+type exposedObjects map[string]bool
+
+func (o exposedObjects) add(kind string, name string) {
+	o[kind+"/"+name] = true
+}
+
+func (o exposedObjects) has(kind string, name string) bool {
+	return o[kind+"/"+name]
+}
+
+// exposeTypes returns the types of exposure requested for the VDB. Unless exposed through 3scale, the OData
+// service is exposed through a Route, Ingress or Gateway, the default is picked based on the APIs supported
+// by the cluster
+func exposeTypes(vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) []v1alpha1.ExposeType {
+	types := []v1alpha1.ExposeType{}
+	httpExposed := false
+	for _, exposeType := range vdb.Spec.Expose {
+		switch exposeType {
+		case v1alpha1.ExposeVia3scale:
+			log.Debug("creation of Route skipped as it is configured to be exposed through 3scale")
+			httpExposed = true
+			continue
+		case v1alpha1.LoadBalancer, v1alpha1.NodePort:
+			if len(containerPorts(vdb, true)) == 0 {
+				log.Debug("secure JDBC and PostgreSQL are disabled, ", exposeType, " service is not created")
+				continue
+			}
+		case v1alpha1.PassthroughRoute:
+			if len(passthroughPorts(vdb)) == 0 {
+				log.Debug("secure JDBC is disabled, passthrough routes are not created")
+				continue
+			}
+			if !hasRouteAPI(r) {
+				log.Debug("Route API is not available on the cluster, passthrough routes are not created")
+				continue
+			}
+		case v1alpha1.Route:
+			if !isODataExposed(vdb) {
+				log.Debug("OData is disabled, Route is not created")
+				continue
+			}
+			if !hasRouteAPI(r) {
+				log.Debug("Route API is not available on the cluster, exposing through Ingress")
+				exposeType = v1alpha1.Ingress
+			}
+			httpExposed = true
+		case v1alpha1.Ingress, v1alpha1.Gateway:
+			if !isODataExposed(vdb) {
+				log.Debug("OData is disabled, ", exposeType, " is not created")
+				continue
+			}
+			httpExposed = true
+		}
+		types = appendExposeType(types, exposeType)
+	}
+
+	if !httpExposed && isODataExposed(vdb) {
+		if hasRouteAPI(r) {
+			types = appendExposeType(types, v1alpha1.Route)
+		} else if vdb.Spec.ExposeOptions != nil && vdb.Spec.ExposeOptions.Gateway != nil && hasGatewayAPI(r) {
+			types = appendExposeType(types, v1alpha1.Gateway)
+		} else {
+			types = appendExposeType(types, v1alpha1.Ingress)
+		}
+	}
+	return types
+}
+
+func appendExposeType(types []v1alpha1.ExposeType, exposeType v1alpha1.ExposeType) []v1alpha1.ExposeType {
+	for _, t := range types {
+		if t == exposeType {
+			return types
+		}
+	}
+	return append(types, exposeType)
+}
+
+func hasRouteAPI(r *ReconcileVirtualDatabase) bool {
+	return kubernetes.HasServerResource(r.client, oroutev1.SchemeGroupVersion.String(), "Route")
+}
+
+// protocolHost host name used for SNI based routing of given port
+func protocolHost(portName string, host string) string {
+	if portName == "teiid-secure" {
+		return "jdbc-" + host
+	}
+	return "pg-" + host
+}
+
+func exposeOptions(vdb *v1alpha1.VirtualDatabase) v1alpha1.ExposeOptionsObject {
+	if vdb.Spec.ExposeOptions != nil {
+		return *vdb.Spec.ExposeOptions
+	}
+	return v1alpha1.ExposeOptionsObject{}
+}
 
+// mergeMetadata copies the labels and annotations of the desired object into existing, the ones added by
+// others are left alone
+func mergeMetadata(existing *metav1.ObjectMeta, desired metav1.ObjectMeta) {
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		existing.Labels[k] = v
+	}
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	for k, v := range desired.Annotations {
+		existing.Annotations[k] = v
+	}
+}
+
+func buildService(vdb *v1alpha1.VirtualDatabase, hasCertSecret bool) corev1.Service {
 	servicePorts := []corev1.ServicePort{}
-	for _, port := range containerPorts(false) {
+	for _, port := range containerPorts(vdb, false) {
+		// http only serves the health checks unless OData is served on it
+		if port.Name == "http" && !isODataEnabled(vdb, false) {
+			continue
+		}
 		servicePorts = append(servicePorts, corev1.ServicePort{
 			Name:       port.Name,
 			Protocol:   port.Protocol,
@@ -134,14 +410,14 @@ func (action *createServiceAction) createService(vdb *v1alpha1.VirtualDatabase,
 	}
 
 	labels := map[string]string{
-		"app":                         vdb.ObjectMeta.Name,
-		"discovery.3scale.net":        "true",
-		"teiid.io/VirtualDatabase":    vdb.ObjectMeta.Name,
-		"teiid.io/type":               "VirtualDatabase",
-		"teiid.io/deployment-version": vdb.Status.Version,
+		"app":                      vdb.ObjectMeta.Name,
+		"discovery.3scale.net":     strconv.FormatBool(isODataExposed(vdb)),
+		"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name,
+		"teiid.io/type":            "VirtualDatabase",
+		deploymentVersionLabel:     servingVersion(vdb),
 	}
 
-	matchLables := matchLabels(vdb.ObjectMeta.Name)
+	matchLables := serviceSelector(vdb)
 
 	// if openapi is in use then use the openapi for it
 	apiLink := "/odata/openapi.json"
@@ -149,10 +425,15 @@ func (action *createServiceAction) createService(vdb *v1alpha1.VirtualDatabase,
 		apiLink = "/openapi.json"
 	}
 
-	annotations := map[string]string{
-		"discovery.3scale.net/scheme":           "http",
-		"discovery.3scale.net/port":             "8080",
-		"discovery.3scale.net/description-path": apiLink,
+	annotations := map[string]string{}
+	if isODataExposed(vdb) {
+		scheme := "http"
+		if isODataEnabled(vdb, true) {
+			scheme = "https"
+		}
+		annotations["discovery.3scale.net/scheme"] = scheme
+		annotations["discovery.3scale.net/port"] = strconv.Itoa(int(odataPort(vdb)))
+		annotations["discovery.3scale.net/description-path"] = apiLink
 	}
 
 	// if there is no secret certificate then annotate to create one
@@ -182,28 +463,63 @@ func (action *createServiceAction) createService(vdb *v1alpha1.VirtualDatabase,
 		},
 	}
 	service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
-	err := controllerutil.SetControllerReference(vdb, &service, r.client.GetScheme())
-	if err != nil {
-		log.Error(err)
-	}
+	return service
+}
 
-	service.ResourceVersion = ""
-	err = kubernetes.EnsureObject(&service,
-		r.client.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, &corev1.Service{}), r.client)
-	if err != nil {
+func (action *createServiceAction) ensureService(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase, hasCertSecret bool) (corev1.Service, error) {
+
+	service := buildService(vdb, hasCertSecret)
+	if err := action.ensureExposedService(ctx, vdb, r, service); err != nil {
 		return corev1.Service{}, err
 	}
 	return service, nil
 }
 
-func (action *createServiceAction) createRoute(service corev1.Service, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (oroutev1.Route, error) {
+// ensureExposedService creates the service or updates the existing one to match the desired
+func (action *createServiceAction) ensureExposedService(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase, desired corev1.Service) error {
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, service, func() error {
+		mergeMetadata(&service.ObjectMeta, desired.ObjectMeta)
+		service.Spec.Type = desired.Spec.Type
+		service.Spec.Selector = desired.Spec.Selector
+		service.Spec.SessionAffinity = desired.Spec.SessionAffinity
+		service.Spec.SessionAffinityConfig = desired.Spec.SessionAffinityConfig
+		service.Spec.Ports = mergeServicePorts(service.Spec.Ports, desired.Spec.Ports)
+		return controllerutil.SetControllerReference(vdb, service, r.client.GetScheme())
+	})
+	return err
+}
+
+// mergeServicePorts returns the desired ports, keeping the node ports already allocated
+func mergeServicePorts(existing []corev1.ServicePort, desired []corev1.ServicePort) []corev1.ServicePort {
+	ports := []corev1.ServicePort{}
+	for _, port := range desired {
+		for _, e := range existing {
+			if e.Name == port.Name && port.NodePort == 0 {
+				port.NodePort = e.NodePort
+			}
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+func buildRoute(service corev1.Service, vdb *v1alpha1.VirtualDatabase) oroutev1.Route {
+	options := exposeOptions(vdb)
 	metadata := service.ObjectMeta.DeepCopy()
 	metadata.Labels["teiid.io/api"] = "odata"
+	for k, v := range options.Annotations {
+		metadata.Annotations[k] = v
+	}
 	route := oroutev1.Route{
 		ObjectMeta: *metadata,
 		Spec: oroutev1.RouteSpec{
+			Host: options.Host,
 			Port: &oroutev1.RoutePort{
-				TargetPort: intstr.FromInt(8080),
+				TargetPort: intstr.FromInt(int(odataPort(vdb))),
 			},
 			To: oroutev1.RouteTargetReference{
 				Kind: "Service",
@@ -214,37 +530,93 @@ func (action *createServiceAction) createRoute(service corev1.Service, vdb *v1al
 			},
 		},
 	}
+	// the service certificate is signed by the service CA which the router trusts
+	if isODataEnabled(vdb, true) {
+		route.Spec.TLS.Termination = oroutev1.TLSTerminationReencrypt
+	}
+	applyCanaryWeights(vdb, &route)
 	route.SetGroupVersionKind(oroutev1.SchemeGroupVersion.WithKind("Route"))
-	err := controllerutil.SetControllerReference(vdb, &route, r.client.GetScheme())
-	if err != nil {
-		log.Error("Error setting controller reference. ", err)
-	}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, &oroutev1.Route{})
-	if err != nil && errors.IsNotFound(err) {
-		route.ResourceVersion = ""
-		err = kubernetes.EnsureObject(&route, err, r.client)
-		if err != nil {
-			log.Error("Error creating Route. ", err)
+	return route
+}
+
+// passthroughPorts the ports a passthrough route can reach, the router picks the route from the SNI of the TLS
+// handshake. PostgreSQL clients send a plaintext SSLRequest first so pg-secure is exposed through a LoadBalancer
+// or NodePort service only
+func passthroughPorts(vdb *v1alpha1.VirtualDatabase) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{}
+	for _, port := range containerPorts(vdb, true) {
+		if port.Name == "teiid-secure" {
+			ports = append(ports, port)
 		}
 	}
+	return ports
+}
 
-	// wait until route is created
-	found := &oroutev1.Route{}
-	for i := 1; i < 60; i++ {
-		time.Sleep(time.Duration(100) * time.Millisecond)
-		err = r.client.Get(context.TODO(), types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, found)
-		if err == nil {
-			break
-		}
+func buildPassthroughRoute(service corev1.Service, vdb *v1alpha1.VirtualDatabase, port corev1.ContainerPort) oroutev1.Route {
+	options := exposeOptions(vdb)
+	metadata := service.ObjectMeta.DeepCopy()
+	metadata.Name = service.Name + "-" + port.Name
+	metadata.Annotations = map[string]string{}
+	for k, v := range options.Annotations {
+		metadata.Annotations[k] = v
+	}
+
+	host := ""
+	if options.Host != "" {
+		host = protocolHost(port.Name, options.Host)
+	}
+	route := oroutev1.Route{
+		ObjectMeta: *metadata,
+		Spec: oroutev1.RouteSpec{
+			Host: host,
+			Port: &oroutev1.RoutePort{
+				TargetPort: intstr.FromString(port.Name),
+			},
+			To: oroutev1.RouteTargetReference{
+				Kind: "Service",
+				Name: service.Name,
+			},
+			TLS: &oroutev1.TLSConfig{
+				Termination: oroutev1.TLSTerminationPassthrough,
+			},
+		},
 	}
-	return *found, err
+	route.SetGroupVersionKind(oroutev1.SchemeGroupVersion.WithKind("Route"))
+	return route
 }
 
-func (action *createServiceAction) createExternalService(vdb *v1alpha1.VirtualDatabase,
-	r *ReconcileVirtualDatabase, name string, serviceType corev1.ServiceType) (corev1.Service, error) {
+// ensureRoute creates the route or updates the existing one to match the desired, returns the route with
+// the host assigned by the cluster
+func (action *createServiceAction) ensureRoute(ctx context.Context, desired oroutev1.Route, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) (oroutev1.Route, error) {
 
+	route := &oroutev1.Route{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.client, route, func() error {
+		mergeMetadata(&route.ObjectMeta, desired.ObjectMeta)
+		// keep the host generated by the cluster and the defaults filled in by it
+		if desired.Spec.Host != "" {
+			route.Spec.Host = desired.Spec.Host
+		}
+		route.Spec.Port = desired.Spec.Port
+		route.Spec.To.Kind = desired.Spec.To.Kind
+		route.Spec.To.Name = desired.Spec.To.Name
+		if desired.Spec.To.Weight != nil {
+			route.Spec.To.Weight = desired.Spec.To.Weight
+		}
+		route.Spec.AlternateBackends = desired.Spec.AlternateBackends
+		route.Spec.TLS = desired.Spec.TLS
+		return controllerutil.SetControllerReference(vdb, route, r.client.GetScheme())
+	})
+	if err != nil {
+		log.Error("Error creating Route. ", err)
+		return oroutev1.Route{}, err
+	}
+	return *route, nil
+}
+
+func buildExternalService(vdb *v1alpha1.VirtualDatabase, name string, serviceType corev1.ServiceType) corev1.Service {
 	servicePorts := []corev1.ServicePort{}
-	for _, port := range containerPorts(true) {
+	for _, port := range containerPorts(vdb, true) {
 		servicePorts = append(servicePorts, corev1.ServicePort{
 			Name:       port.Name,
 			Protocol:   port.Protocol,
@@ -260,7 +632,7 @@ func (action *createServiceAction) createExternalService(vdb *v1alpha1.VirtualDa
 		"teiid.io/type":            "VirtualDatabase",
 	}
 
-	matchLables := matchLabels(vdb.ObjectMeta.Name)
+	matchLables := serviceSelector(vdb)
 
 	meta := metav1.ObjectMeta{
 		Name:      name,
@@ -283,16 +655,5 @@ func (action *createServiceAction) createExternalService(vdb *v1alpha1.VirtualDa
 		},
 	}
 	service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
-	err := controllerutil.SetControllerReference(vdb, &service, r.client.GetScheme())
-	if err != nil {
-		log.Error(err)
-	}
-
-	service.ResourceVersion = ""
-	err = kubernetes.EnsureObject(&service,
-		r.client.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, &corev1.Service{}), r.client)
-	if err != nil {
-		return corev1.Service{}, err
-	}
-	return service, nil
+	return service
 }
@@ -0,0 +1,88 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"errors"
+	"testing"
+
+	oroutev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergeServicePorts(t *testing.T) {
+	existing := []corev1.ServicePort{
+		{Name: "teiid-secure", Port: 31000, NodePort: 30100},
+		{Name: "pg-secure", Port: 5433, NodePort: 30200},
+	}
+	desired := []corev1.ServicePort{
+		{Name: "teiid-secure", Port: 31000},
+		{Name: "pg", Port: 5432},
+	}
+	ports := mergeServicePorts(existing, desired)
+	assert.Equal(t, 2, len(ports))
+	assert.Equal(t, int32(30100), ports[0].NodePort)
+	assert.Equal(t, int32(0), ports[1].NodePort)
+	assert.Equal(t, int32(0), desired[0].NodePort)
+}
+
+func TestStaleExposedObjects(t *testing.T) {
+	vdb := testVdb()
+	vdb.UID = "1234"
+	vdb.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("VirtualDatabase"))
+	owned := true
+	ownerRefs := []metav1.OwnerReference{
+		{APIVersion: "teiid.io/v1alpha1", Kind: "VirtualDatabase", Name: "dv", UID: "1234", Controller: &owned},
+	}
+
+	desired := exposedObjects{}
+	desired.add("Service", "dv")
+	desired.add("Route", "dv")
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "dv", OwnerReferences: ownerRefs}}
+	assert.False(t, isStaleExposedObject(service, vdb, desired))
+
+	external := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "dv-external", OwnerReferences: ownerRefs}}
+	assert.True(t, isStaleExposedObject(external, vdb, desired))
+
+	// objects not owned by the vdb are never removed
+	other := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "dv-external"}}
+	assert.False(t, isStaleExposedObject(other, vdb, desired))
+
+	route := &oroutev1.Route{ObjectMeta: metav1.ObjectMeta{Name: "dv-teiid-secure", OwnerReferences: ownerRefs}}
+	assert.True(t, isStaleExposedObject(route, vdb, desired))
+	desired.add("Route", "dv-teiid-secure")
+	assert.False(t, isStaleExposedObject(route, vdb, desired))
+}
+
+func TestSetExposureFailure(t *testing.T) {
+	vdb := &v1alpha1.VirtualDatabase{}
+	setExposureFailure(vdb, errors.New("Failed to create Ingress, forbidden"))
+	assert.Equal(t, "Failed to expose the Virtual Database: Failed to create Ingress, forbidden", vdb.Status.Failure)
+
+	setExposureFailure(vdb, nil)
+	assert.Equal(t, "", vdb.Status.Failure)
+
+	// failures of the other steps are left alone
+	vdb.Status.Failure = "verification of version 2 failed"
+	setExposureFailure(vdb, nil)
+	assert.Equal(t, "verification of version 2 failed", vdb.Status.Failure)
+}
@@ -0,0 +1,159 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
+)
+
+// artifactResolveTimeout bounds every request to a maven repository
+const artifactResolveTimeout = 10 * time.Second
+
+// dataSourceDependencies returns the driver and translator artifacts of the Data Sources
+func dataSourceDependencies(sources []v1alpha1.DataSourceObject) ([]maven.Dependency, error) {
+	deps := make([]maven.Dependency, 0)
+	for _, ds := range sources {
+		if ds.Driver != nil {
+			d, err := maven.ParseGAV(ds.Driver.Maven)
+			if err != nil {
+				return nil, fmt.Errorf("invalid driver of the Data Source %s: %v", ds.Name, err)
+			}
+			deps = append(deps, d)
+		}
+		if ds.Translator != nil {
+			d, err := maven.ParseGAV(ds.Translator.Maven)
+			if err != nil {
+				return nil, fmt.Errorf("invalid translator of the Data Source %s: %v", ds.Name, err)
+			}
+			deps = append(deps, d)
+		}
+	}
+	return deps, nil
+}
+
+// validateTranslatorNames makes sure the custom translators match the FOREIGN DATA WRAPPER of the servers in the DDL
+func validateTranslatorNames(sources []v1alpha1.DataSourceObject, sourcesFromDdl []vdbutil.DatasourceInfo) error {
+	for _, ds := range sources {
+		if ds.Translator == nil {
+			continue
+		}
+		if ds.Translator.Name == "" {
+			return fmt.Errorf("the translator of the Data Source %s has no name", ds.Name)
+		}
+		found := false
+		for _, s := range sourcesFromDdl {
+			if !strings.EqualFold(s.Name, ds.Name) {
+				continue
+			}
+			found = true
+			if !strings.EqualFold(s.Type, ds.Translator.Name) {
+				return fmt.Errorf("the translator %s of the Data Source %s does not match the FOREIGN DATA WRAPPER %s in the DDL",
+					ds.Translator.Name, ds.Name, strings.ToLower(s.Type))
+			}
+		}
+		if !found {
+			return fmt.Errorf("the Data Source %s with a translator is not defined as a SERVER in the DDL", ds.Name)
+		}
+	}
+	return nil
+}
+
+// dataSourceRepositories returns the repositories the build resolves the artifacts from, with the mirror, the
+// credentials and the profiles of the maven settings of the build
+func dataSourceRepositories(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) ([]maven.RemoteRepository, error) {
+	content, err := readMavenSettingsFile(ctx, vdb, r, configuredRepositories(vdb))
+	if err != nil {
+		return nil, err
+	}
+	settings, err := maven.ParseSettings(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maven settings: %v", err)
+	}
+	var profiles []string
+	if vdb.Spec.Build.Maven != nil {
+		profiles = vdb.Spec.Build.Maven.Profiles
+	}
+	return maven.RemoteRepositories(settings, profiles), nil
+}
+
+// resolveDataSources checks the drivers and translators of the Data Sources can be resolved from the maven
+// repositories and returns their status. Only an artifact every repository answered is missing fails the Virtual
+// Database, when a repository can not be reached the build decides. The translator names are only checked against
+// the DDL when it is part of the resource, the DDL of a maven based Virtual Database is checked when the image is
+// built
+func resolveDataSources(vdb *v1alpha1.VirtualDatabase, repos []maven.RemoteRepository) ([]v1alpha1.DataSourceStatus, error) {
+	if vdb.Spec.Build.Source.DDL != "" {
+		if err := validateTranslatorNames(vdb.Spec.DataSources, vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL)); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := &http.Client{Timeout: artifactResolveTimeout}
+	var statuses []v1alpha1.DataSourceStatus
+	for _, ds := range vdb.Spec.DataSources {
+		if ds.Driver == nil && ds.Translator == nil {
+			continue
+		}
+		status := v1alpha1.DataSourceStatus{Name: ds.Name, Resolved: true}
+		if ds.Driver != nil {
+			resolved, err := resolveArtifact(ds.Name, "driver", ds.Driver.Maven, repos, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			status.Driver = ds.Driver.Maven
+			status.Resolved = status.Resolved && resolved
+		}
+		if ds.Translator != nil {
+			resolved, err := resolveArtifact(ds.Name, "translator", ds.Translator.Maven, repos, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			status.Translator = ds.Translator.Name
+			status.TranslatorArtifact = ds.Translator.Maven
+			status.Resolved = status.Resolved && resolved
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// resolveArtifact tells whether the artifact was found, an error is returned only when it is invalid or missing
+// from all the repositories
+func resolveArtifact(name string, kind string, gav string, repos []maven.RemoteRepository, httpClient *http.Client) (bool, error) {
+	d, err := maven.ParseGAV(gav)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s of the Data Source %s: %v", kind, name, err)
+	}
+	url, err := maven.ResolveDependency(d, repos, httpClient)
+	if _, ok := err.(maven.NotFoundError); ok {
+		return false, fmt.Errorf("the %s of the Data Source %s can not be resolved: %v", kind, name, err)
+	} else if err != nil {
+		log.Warnf("the %s of the Data Source %s could not be checked, leaving it to the build: %v", kind, name, err)
+		return false, nil
+	}
+	log.Debugf("resolved the %s of the Data Source %s at %s", kind, name, url)
+	return true, nil
+}
@@ -0,0 +1,137 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
+)
+
+const customSourceDdl = `CREATE DATABASE customer OPTIONS (ANNOTATION 'Customer VDB');
+	USE DATABASE customer;
+	CREATE SERVER legacydb FOREIGN DATA WRAPPER legacy;
+	CREATE SERVER sampledb FOREIGN DATA WRAPPER postgresql;
+	CREATE SCHEMA accounts SERVER legacydb;`
+
+func customSourceVdb(repository string) *v1alpha1.VirtualDatabase {
+	vdb := &v1alpha1.VirtualDatabase{}
+	vdb.ObjectMeta.Name = "customer"
+	vdb.Spec.Build.Source.DDL = customSourceDdl
+	vdb.Spec.Build.Source.MavenRepositories = map[string]string{"test": repository}
+	vdb.Spec.DataSources = []v1alpha1.DataSourceObject{
+		{
+			Name:       "legacydb",
+			Type:       "legacy",
+			Driver:     &v1alpha1.DriverObject{Maven: "com.example:legacy-jdbc:1.2.0"},
+			Translator: &v1alpha1.TranslatorObject{Maven: "com.example:translator-legacy:1.0.0", Name: "legacy"},
+		},
+		{
+			Name: "sampledb",
+			Type: "postgresql",
+		},
+	}
+	return vdb
+}
+
+func TestDataSourceDependencies(t *testing.T) {
+	vdb := customSourceVdb("http://localhost")
+	deps, err := dataSourceDependencies(vdb.Spec.DataSources)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(deps))
+	assert.Equal(t, "legacy-jdbc", deps[0].ArtifactID)
+	assert.Equal(t, "1.2.0", deps[0].Version)
+	assert.Equal(t, "translator-legacy", deps[1].ArtifactID)
+
+	project, err := GenerateVdbPom(vdb, vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL), constants.ConnectionFactories, false, false, false)
+	assert.Nil(t, err)
+	assert.True(t, hasDependency(project, "com.example", "legacy-jdbc"))
+	assert.True(t, hasDependency(project, "com.example", "translator-legacy"))
+	assert.True(t, hasDependency(project, "org.teiid", "spring-data-postgresql"))
+
+	vdb.Spec.DataSources[0].Driver.Maven = "legacy-jdbc"
+	_, err = dataSourceDependencies(vdb.Spec.DataSources)
+	assert.NotNil(t, err)
+}
+
+func TestValidateTranslatorNames(t *testing.T) {
+	vdb := customSourceVdb("http://localhost")
+	sources := vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL)
+	assert.Nil(t, validateTranslatorNames(vdb.Spec.DataSources, sources))
+
+	vdb.Spec.DataSources[0].Translator.Name = "LEGACY"
+	assert.Nil(t, validateTranslatorNames(vdb.Spec.DataSources, sources))
+
+	vdb.Spec.DataSources[0].Translator.Name = "legacy2"
+	err := validateTranslatorNames(vdb.Spec.DataSources, sources)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "FOREIGN DATA WRAPPER legacy")
+
+	vdb.Spec.DataSources[0].Translator.Name = "legacy"
+	vdb.Spec.DataSources[0].Name = "otherdb"
+	assert.NotNil(t, validateTranslatorNames(vdb.Spec.DataSources, sources))
+}
+
+func TestResolveDataSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/com/example/legacy-jdbc/1.2.0/legacy-jdbc-1.2.0.jar",
+			"/com/example/translator-legacy/1.0.0/translator-legacy-1.0.0.jar":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	repos := []maven.RemoteRepository{{ID: "test", URL: server.URL}}
+
+	vdb := customSourceVdb(server.URL)
+	statuses, err := resolveDataSources(vdb, repos)
+	assert.Nil(t, err)
+	assert.Equal(t, []v1alpha1.DataSourceStatus{{
+		Name:               "legacydb",
+		Driver:             "com.example:legacy-jdbc:1.2.0",
+		Translator:         "legacy",
+		TranslatorArtifact: "com.example:translator-legacy:1.0.0",
+		Resolved:           true,
+	}}, statuses)
+
+	vdb.Spec.DataSources[0].Driver.Maven = "com.example:legacy-jdbc:9.9.9"
+	_, err = resolveDataSources(vdb, repos)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "driver of the Data Source legacydb")
+
+	vdb = customSourceVdb(server.URL)
+	vdb.Spec.DataSources[0].Translator.Name = "other"
+	_, err = resolveDataSources(vdb, repos)
+	assert.NotNil(t, err)
+
+	// a repository that can not be reached leaves the decision to the build
+	server.Close()
+	vdb = customSourceVdb(server.URL)
+	statuses, err = resolveDataSources(vdb, repos)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(statuses))
+	assert.False(t, statuses[0].Resolved)
+}
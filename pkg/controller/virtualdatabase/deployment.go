@@ -19,7 +19,7 @@ package virtualdatabase
 
 import (
 	"context"
-	"reflect"
+	"fmt"
 
 	obuildv1 "github.com/openshift/api/build/v1"
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
@@ -30,8 +30,9 @@ import (
 	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -66,37 +67,39 @@ func (action *deploymentAction) Handle(ctx context.Context, vdb *v1alpha1.Virtua
 			return err
 		}
 
-		existing, err := findDC(vdb, r)
-		if err != nil {
-			dc, err2 := action.buildDeployment(vdb, *bc, r)
-			if err2 != nil {
-				return err2
-			}
-
-			_, err = r.client.AppsV1().Deployments(vdb.ObjectMeta.Namespace).Create(&dc)
-			//err = kubernetes.EnsureObject(&dc, err, r.client)
-			if err != nil {
-				return err
-			}
+		if usesStatefulSet(vdb) {
+			err = action.ensureStatefulSet(ctx, vdb, *bc, r)
+		} else if isVersioned(vdb) {
+			err = action.ensureVersionDeployment(ctx, vdb, *bc, r)
 		} else {
-			// if a new image is created then update the deployment with it
-			if existing.Spec.Template.Spec.Containers[0].Image != bc.Spec.Output.To.Name {
-				existing.Spec.Template.Spec.Containers[0].Image = bc.Spec.Output.To.Name
-				_, err = r.client.AppsV1().Deployments(vdb.ObjectMeta.Namespace).Update(existing)
-				//err = r.client.Update(context.TODO(), existing)
-				if err != nil {
-					log.Warn("Failed to update object. ", err)
-					return err
-				}
-			}
+			err = action.ensureDeployment(ctx, vdb, *bc, r)
+		}
+		if err != nil {
+			return err
 		}
 
 		// change the status, needs to be done before next method.
 		vdb.Status.Phase = v1alpha1.ReconcilerPhaseDeploying
 		return nil
+	} else if usesStatefulSet(vdb) {
+		return action.handleStatefulSet(ctx, vdb, r)
+	} else if isVersioned(vdb) {
+		return action.handleVersions(ctx, vdb, r)
 	} else if vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying {
-		item, _ := findDC(vdb, r)
+		item, err := findDC(vdb, r)
+		if k8serrors.IsNotFound(err) {
+			// switched from a StatefulSet or removed, create it again
+			vdb.Status.Phase = v1alpha1.ReconcilerPhaseKeystoreCreated
+			return nil
+		}
+		if item != nil {
+			updateReplicaStatus(vdb, *item)
+		}
 		if item != nil && action.isDeploymentInReadyState(*item) {
+			// the StatefulSet serves the clients until the Deployment is ready
+			if err := removeStatefulSet(ctx, vdb, r); err != nil {
+				return err
+			}
 			log.Info("Deployment finished:" + vdb.ObjectMeta.Name)
 			vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
 		} else if item != nil && !action.isDeploymentProgressing(*item) {
@@ -104,12 +107,55 @@ func (action *deploymentAction) Handle(ctx context.Context, vdb *v1alpha1.Virtua
 			vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
 		}
 	} else if vdb.Status.Phase == v1alpha1.ReconcilerPhaseRunning {
-		item, _ := findDC(vdb, r)
+		item, err := findDC(vdb, r)
+		if k8serrors.IsNotFound(err) {
+			vdb.Status.Phase = v1alpha1.ReconcilerPhaseKeystoreCreated
+			return nil
+		}
+		if item != nil {
+			updateReplicaStatus(vdb, *item)
+		}
 		if item != nil && action.isDeploymentInReadyState(*item) {
 			err := action.ensureReplicas(ctx, vdb, item, r)
 			if err != nil {
 				return err
 			}
+			// switched from the BlueGreen strategy, the Deployments of the versions are no longer needed
+			vdb.Status.ActiveVersion = ""
+			vdb.Status.PreviousVersion = ""
+			vdb.Status.PreviousVersionExpiry = nil
+			return removeStaleDeployments(ctx, vdb, []string{vdb.ObjectMeta.Name}, r)
+		}
+	}
+	return nil
+}
+
+// ensureDeployment creates the Deployment, or updates the image of the existing one. The StatefulSet used by the
+// PerReplica buffer storage is removed once the Deployment is ready
+func (action *deploymentAction) ensureDeployment(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	bc obuildv1.BuildConfig, r *ReconcileVirtualDatabase) error {
+
+	existing, err := findDC(vdb, r)
+	if err != nil {
+		dc, err2 := action.buildDeployment(vdb, bc, r)
+		if err2 != nil {
+			return err2
+		}
+
+		_, err = r.client.AppsV1().Deployments(vdb.ObjectMeta.Namespace).Create(&dc)
+		//err = kubernetes.EnsureObject(&dc, err, r.client)
+		if err != nil {
+			return err
+		}
+	} else {
+		// if a new image is created then update the deployment with it
+		if setImage(&existing.Spec.Template.Spec.Containers[0], deploymentImage(vdb, bc)) {
+			_, err = r.client.AppsV1().Deployments(vdb.ObjectMeta.Namespace).Update(existing)
+			//err = r.client.Update(context.TODO(), existing)
+			if err != nil {
+				log.Warn("Failed to update object. ", err)
+				return err
+			}
 		}
 	}
 	return nil
@@ -118,18 +164,53 @@ func (action *deploymentAction) Handle(ctx context.Context, vdb *v1alpha1.Virtua
 func (action *deploymentAction) ensureReplicas(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
 	item *appsv1.Deployment, r *ReconcileVirtualDatabase) error {
 
-	deploymentEnvs, err := deploymentEnvironments(vdb, r)
+	update, err := action.syncPodTemplate(ctx, vdb, &item.ObjectMeta, &item.Spec.Replicas, &item.Spec.Template, r)
 	if err != nil {
 		return err
 	}
+	if update {
+		if err := r.client.Update(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	update := false
-	if *vdb.Spec.Replicas != *item.Spec.Replicas {
-		item.Spec.Replicas = vdb.Spec.Replicas
-		update = true
+// syncPodTemplate brings the replicas and the pod template of the Deployment or StatefulSet in line with the vdb,
+// returns true when the object needs to be updated
+func (action *deploymentAction) syncPodTemplate(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	meta *metav1.ObjectMeta, replicas **int32, template *corev1.PodTemplateSpec, r *ReconcileVirtualDatabase) (bool, error) {
+
+	update := syncReplicas(vdb, replicas)
+
+	source, err := podTemplateSource(vdb)
+	if err != nil {
+		return false, err
 	}
-	if !reflect.DeepEqual(deploymentEnvs, item.Spec.Template.Spec.Containers[0].Env) {
-		item.Spec.Template.Spec.Containers[0].Env = deploymentEnvs
+	if meta.Annotations[podTemplateSourceAnnotation] != source {
+		deploymentEnvs, err := deploymentEnvironments(vdb, r)
+		if err != nil {
+			return false, err
+		}
+		template.Spec.Containers[0].Env = deploymentEnvs
+
+		// scheduling or pod template changed, replace the template with the generated one
+		bc, err := r.buildClient.BuildConfigs(vdb.ObjectMeta.Namespace).Get(vdb.ObjectMeta.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		desired, err := action.buildDeployment(vdb, *bc, r)
+		if err != nil {
+			return false, err
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		if meta.Annotations[podTemplateDigestAnnotation] != desired.Annotations[podTemplateDigestAnnotation] {
+			meta.Annotations[podTemplateDigestAnnotation] = desired.Annotations[podTemplateDigestAnnotation]
+			*template = desired.Spec.Template
+		}
+		meta.Annotations[podTemplateSourceAnnotation] = source
 		update = true
 	}
 
@@ -137,28 +218,51 @@ func (action *deploymentAction) ensureReplicas(ctx context.Context, vdb *v1alpha
 		// check to see if any of the secrets or configmaps changed
 		configdigest, err := ComputeConfigDigest(ctx, r.client, vdb)
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		if configdigest != vdb.Status.ConfigDigest {
 			log.Info("ConfigMap or Secret has changed redeploying")
 			update = true
 			vdb.Status.ConfigDigest = configdigest
-			item.Spec.Template.ObjectMeta.Annotations["configHash"] = configdigest
+			template.ObjectMeta.Annotations["configHash"] = configdigest
 		}
 	}
+	return update, nil
+}
 
-	if update {
-		if err := r.client.Update(ctx, item); err != nil {
-			return err
-		}
+// syncReplicas sets the replicas of the vdb, returns true when they changed
+func syncReplicas(vdb *v1alpha1.VirtualDatabase, replicas **int32) bool {
+	if *replicas != nil && *vdb.Spec.Replicas == **replicas {
+		return false
 	}
-	return nil
+	*replicas = vdb.Spec.Replicas
+	return true
+}
+
+// applyScheduling sets the affinity, tolerations, node selector, topology spread and priority of the pods
+func applyScheduling(vdb *v1alpha1.VirtualDatabase, spec *corev1.PodSpec) {
+	spec.Affinity = vdb.Spec.Affinity
+	spec.Tolerations = vdb.Spec.Tolerations
+	spec.NodeSelector = vdb.Spec.NodeSelector
+	spec.TopologySpreadConstraints = vdb.Spec.TopologySpreadConstraints
+	spec.PriorityClassName = vdb.Spec.PriorityClassName
+}
+
+// updateReplicaStatus mirrors the replicas of the Deployment in the status, read by the scale subresource
+func updateReplicaStatus(vdb *v1alpha1.VirtualDatabase, dc appsv1.Deployment) {
+	vdb.Status.Replicas = dc.Status.Replicas
+	vdb.Status.ReadyReplicas = dc.Status.ReadyReplicas
+	vdb.Status.Selector = labels.SelectorFromSet(serviceSelector(vdb)).String()
 }
 
 func findDC(vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (*appsv1.Deployment, error) {
+	return findDeployment(vdb, vdb.ObjectMeta.Name, r)
+}
+
+func findDeployment(vdb *v1alpha1.VirtualDatabase, name string, r *ReconcileVirtualDatabase) (*appsv1.Deployment, error) {
 	obj := appsv1.Deployment{}
-	key := client.ObjectKey{Namespace: vdb.ObjectMeta.Namespace, Name: vdb.ObjectMeta.Name}
+	key := client.ObjectKey{Namespace: vdb.ObjectMeta.Namespace, Name: name}
 	err := r.client.Get(context.TODO(), key, &obj)
 	return &obj, err
 }
@@ -215,7 +319,17 @@ func deploymentEnvironments(vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDa
 	}
 	dataSourceInfos := vdbutil.ParseDataSourcesInfoFromDdl(ddlString)
 	log.Debug(dataSourceInfos)
-	dataSourceConfig, err := convert2SpringProperties(vdb.Spec.DataSources, dataSourceInfos)
+	factories, err := connectionFactories(context.TODO(), r, vdb.ObjectMeta.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	// the property prefixes must match the ones the image was built with, a changed source type is rebuilt first
+	if digest, err := ComputeForVirtualDatabase(vdb, factories); err != nil {
+		return nil, err
+	} else if digest != vdb.Status.Digest {
+		return nil, fmt.Errorf("ConnectionFactories used by %s changed, waiting for the rebuild", vdb.ObjectMeta.Name)
+	}
+	dataSourceConfig, err := convert2SpringProperties(vdb.Spec.DataSources, dataSourceInfos, factories)
 	if err != nil {
 		return nil, err
 	}
@@ -226,20 +340,41 @@ func deploymentEnvironments(vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDa
 	if vdb.Status.CacheStore != "" {
 		defaultEnvs = envvar.Combine(defaultEnvs, cachestore.CredentialsAsEnv(vdb.ObjectMeta.Name, vdb.ObjectMeta.Namespace, r.client))
 	}
+	defaultEnvs = envvar.Combine(defaultEnvs, oidcEnvironments(vdb))
+	defaultEnvs = envvar.Combine(defaultEnvs, bufferStorageEnvironments(vdb))
+	defaultEnvs = envvar.Combine(defaultEnvs, healthGroupEnvironments())
+	resources, err := computingResources(context.TODO(), vdb, r)
+	if err != nil {
+		return nil, err
+	}
+	defaultEnvs = envvar.Combine(defaultEnvs, jvmEnvironments(resources))
 	return envvar.Combine(defaultEnvs, dataSourceConfig), nil
 }
 
-func containerPorts(externalOnly bool) []corev1.ContainerPort {
+// containerPorts returns the ports of the enabled protocols, externalOnly limits them to the secure JDBC and
+// PostgreSQL ports that are exposed outside of the cluster. The http port always serves the health checks
+func containerPorts(vdb *v1alpha1.VirtualDatabase, externalOnly bool) []corev1.ContainerPort {
 	ports := []corev1.ContainerPort{}
 	if !externalOnly {
 		ports = append(ports, corev1.ContainerPort{Name: "http", ContainerPort: int32(8080), Protocol: corev1.ProtocolTCP})
+		if isODataEnabled(vdb, true) {
+			ports = append(ports, corev1.ContainerPort{Name: "https", ContainerPort: int32(8443), Protocol: corev1.ProtocolTCP})
+		}
 		ports = append(ports, corev1.ContainerPort{Name: "jolokia", ContainerPort: int32(8778), Protocol: corev1.ProtocolTCP})
 		ports = append(ports, corev1.ContainerPort{Name: "prometheus", ContainerPort: int32(9779), Protocol: corev1.ProtocolTCP})
-		ports = append(ports, corev1.ContainerPort{Name: "teiid", ContainerPort: int32(31000), Protocol: corev1.ProtocolTCP})
-		ports = append(ports, corev1.ContainerPort{Name: "pg", ContainerPort: int32(35432), Protocol: corev1.ProtocolTCP})
+		if isJDBCEnabled(vdb, false) {
+			ports = append(ports, corev1.ContainerPort{Name: "teiid", ContainerPort: int32(31000), Protocol: corev1.ProtocolTCP})
+		}
+		if isPGEnabled(vdb, false) {
+			ports = append(ports, corev1.ContainerPort{Name: "pg", ContainerPort: int32(35432), Protocol: corev1.ProtocolTCP})
+		}
+	}
+	if isJDBCEnabled(vdb, true) {
+		ports = append(ports, corev1.ContainerPort{Name: "teiid-secure", ContainerPort: int32(31443), Protocol: corev1.ProtocolTCP})
+	}
+	if isPGEnabled(vdb, true) {
+		ports = append(ports, corev1.ContainerPort{Name: "pg-secure", ContainerPort: int32(35443), Protocol: corev1.ProtocolTCP})
 	}
-	ports = append(ports, corev1.ContainerPort{Name: "teiid-secure", ContainerPort: int32(31443), Protocol: corev1.ProtocolTCP})
-	ports = append(ports, corev1.ContainerPort{Name: "pg-secure", ContainerPort: int32(35443), Protocol: corev1.ProtocolTCP})
 	return ports
 }
 
@@ -256,10 +391,14 @@ func matchLabels(vdbName string) map[string]string {
 func (action *deploymentAction) buildDeployment(vdb *v1alpha1.VirtualDatabase, serviceBC obuildv1.BuildConfig,
 	r *ReconcileVirtualDatabase) (appsv1.Deployment, error) {
 
-	var probe *corev1.Probe
 	matchLabels := matchLabels(vdb.ObjectMeta.Name)
-	computingResources := constants.GetComputingResources(vdb)
+	resources, err := computingResources(context.TODO(), vdb, r)
+	if err != nil {
+		vdb.Status.Failure = err.Error()
+		return appsv1.Deployment{}, err
+	}
 
+	image := deploymentImage(vdb, serviceBC)
 	labels := map[string]string{
 		"app":                      vdb.Name,
 		"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name,
@@ -275,18 +414,8 @@ func (action *deploymentAction) buildDeployment(vdb *v1alpha1.VirtualDatabase, s
 		"configHash": vdb.Status.ConfigDigest,
 	}
 
-	// liveness and readiness probes
-	probe = &corev1.Probe{
-		TimeoutSeconds:      int32(5),
-		PeriodSeconds:       int32(20),
-		SuccessThreshold:    int32(1),
-		FailureThreshold:    int32(5),
-		InitialDelaySeconds: int32(15),
-	}
-	probe.Handler.HTTPGet = &corev1.HTTPGetAction{
-		Path: "/actuator/health",
-		Port: intstr.FromInt(8080),
-	}
+	// liveness, readiness and startup probes
+	liveness, readiness, startup := containerProbes(vdb)
 
 	// convert data source properties into ENV properties
 	deploymentEnvs, err := deploymentEnvironments(vdb, r)
@@ -323,12 +452,13 @@ func (action *deploymentAction) buildDeployment(vdb *v1alpha1.VirtualDatabase, s
 						{
 							Name:            vdb.ObjectMeta.Name,
 							Env:             deploymentEnvs,
-							Resources:       computingResources,
-							Image:           serviceBC.Spec.Output.To.Name,
-							ImagePullPolicy: corev1.PullAlways,
-							Ports:           containerPorts(false),
-							LivenessProbe:   probe,
-							ReadinessProbe:  probe,
+							Resources:       resources,
+							Image:           image,
+							ImagePullPolicy: imagePullPolicy(image),
+							Ports:           containerPorts(vdb, false),
+							LivenessProbe:   liveness,
+							ReadinessProbe:  readiness,
+							StartupProbe:    startup,
 							WorkingDir:      "/deployments",
 							VolumeMounts: []corev1.VolumeMount{
 								{
@@ -354,6 +484,25 @@ func (action *deploymentAction) buildDeployment(vdb *v1alpha1.VirtualDatabase, s
 		},
 	}
 
+	applyScheduling(vdb, &dc.Spec.Template.Spec)
+	if err = applyBufferStorage(vdb, &dc.Spec.Template.Spec); err != nil {
+		return appsv1.Deployment{}, err
+	}
+	applyPodTemplate(vdb, &dc.Spec.Template)
+	if isVersioned(vdb) {
+		applyVersion(vdb, &dc)
+	}
+	digest, err := podTemplateDigest(dc.Spec.Template)
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+	dc.ObjectMeta.Annotations[podTemplateDigestAnnotation] = digest
+	source, err := podTemplateSource(vdb)
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+	dc.ObjectMeta.Annotations[podTemplateSourceAnnotation] = source
+
 	// Inject Jaeger agent as side car into the deployment
 	if vdb.Spec.Jaeger != "" && r.jaegerClient.Jaegers(vdb.ObjectMeta.Namespace).HasJaeger(vdb.Spec.Jaeger) {
 		dc.ObjectMeta.Annotations["sidecar.jaegertracing.io/inject"] = vdb.Spec.Jaeger
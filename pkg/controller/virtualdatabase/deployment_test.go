@@ -22,9 +22,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	"github.com/teiid/teiid-operator/pkg/util/envvar"
 	"github.com/teiid/teiid-operator/pkg/util/proxy"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestHandleClusterProxySettings(t *testing.T) {
@@ -82,3 +85,35 @@ func TestMatchLabels(t *testing.T) {
 	assert.Equal(t, "foo", labels["teiid.io/VirtualDatabase"])
 	assert.Equal(t, "VirtualDatabase", labels["teiid.io/type"])
 }
+
+func TestUpdateReplicaStatus(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	dc := appsv1.Deployment{Status: appsv1.DeploymentStatus{Replicas: 3, ReadyReplicas: 2}}
+	updateReplicaStatus(&vdb, dc)
+	assert.Equal(t, int32(3), vdb.Status.Replicas)
+	assert.Equal(t, int32(2), vdb.Status.ReadyReplicas)
+	assert.Equal(t, "app=foo,teiid.io/VirtualDatabase=foo,teiid.io/type=VirtualDatabase", vdb.Status.Selector)
+}
+
+func TestApplyScheduling(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	vdb.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/worker": ""}
+	vdb.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "dv", Effect: corev1.TaintEffectNoSchedule}}
+	vdb.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: matchLabels("foo")},
+		},
+	}
+	vdb.Spec.PriorityClassName = "high-priority"
+
+	spec := corev1.PodSpec{}
+	applyScheduling(&vdb, &spec)
+	assert.Equal(t, vdb.Spec.NodeSelector, spec.NodeSelector)
+	assert.Equal(t, "dedicated", spec.Tolerations[0].Key)
+	assert.Equal(t, "topology.kubernetes.io/zone", spec.TopologySpreadConstraints[0].TopologyKey)
+	assert.Equal(t, "high-priority", spec.PriorityClassName)
+	assert.Nil(t, spec.Affinity)
+}
@@ -21,11 +21,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"math/rand"
 	"strconv"
 
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
 	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -55,9 +57,9 @@ func ComputeConfigDigest(ctx context.Context, client k8sclient.Reader, vdb *v1al
 	return configdigest, nil
 }
 
-// ComputeForVirtualDatabase a digest of the fields that are relevant for the build
-// Produces a digest that can be used as docker image tag
-func ComputeForVirtualDatabase(vdb *v1alpha1.VirtualDatabase) (string, error) {
+// ComputeForVirtualDatabase a digest of the fields that are relevant for the build and of the source types the vdb
+// uses. Produces a digest that can be used as docker image tag
+func ComputeForVirtualDatabase(vdb *v1alpha1.VirtualDatabase, factories map[string]conf.ConnectionFactory) (string, error) {
 	hash := sha256.New()
 	// Operator version is relevant
 	if _, err := hash.Write([]byte(constants.Version)); err != nil {
@@ -92,6 +94,64 @@ func ComputeForVirtualDatabase(vdb *v1alpha1.VirtualDatabase) (string, error) {
 		}
 	}
 
+	// drivers and translators of the data sources
+	for _, source := range vdb.Spec.DataSources {
+		if source.Driver != nil {
+			if _, err := hash.Write([]byte(source.Driver.Maven)); err != nil {
+				return "", err
+			}
+		}
+		if source.Translator != nil {
+			if _, err := hash.Write([]byte(source.Translator.Maven + source.Translator.Name)); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// dependencies and property prefix of the source types, ConnectionFactory resources may change them
+	for _, cf := range usedConnectionFactories(vdb, factories) {
+		factory, err := json.Marshal(cf)
+		if err != nil {
+			return "", err
+		}
+		if _, err := hash.Write(factory); err != nil {
+			return "", err
+		}
+	}
+
+	// arguments, profiles and builder image of the Maven build
+	if vdb.Spec.Build.Maven != nil {
+		maven, err := json.Marshal(vdb.Spec.Build.Maven)
+		if err != nil {
+			return "", err
+		}
+		if _, err := hash.Write(maven); err != nil {
+			return "", err
+		}
+	}
+
+	// security configuration is baked into the application properties
+	if vdb.Spec.Security != nil {
+		security, err := json.Marshal(vdb.Spec.Security)
+		if err != nil {
+			return "", err
+		}
+		if _, err := hash.Write(security); err != nil {
+			return "", err
+		}
+	}
+
+	// listeners are switched on and off in the application properties
+	if vdb.Spec.Protocols != nil {
+		protocols, err := json.Marshal(vdb.Spec.Protocols)
+		if err != nil {
+			return "", err
+		}
+		if _, err := hash.Write(protocols); err != nil {
+			return "", err
+		}
+	}
+
 	// Add a letter at the beginning and use URL safe encoding
 	digest := "v" + base64.RawURLEncoding.EncodeToString(hash.Sum(nil))
 	return digest, nil
@@ -0,0 +1,199 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+
+	oroutev1 "github.com/openshift/api/route/v1"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// refreshEndpoints updates the endpoints and route in the status of the vdb
+func refreshEndpoints(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	endpoints, err := resolveEndpoints(ctx, vdb, r)
+	if err != nil {
+		return err
+	}
+	vdb.Status.Endpoints = endpoints
+	vdb.Status.Route = ""
+	for _, ep := range endpoints {
+		if ep.Protocol == v1alpha1.ODataProtocol && ep.ExternalURL != "" {
+			vdb.Status.Route = ep.ExternalURL
+		}
+	}
+	return nil
+}
+
+// resolveEndpoints reads the Services, Routes, Ingress and Gateway routes of the vdb and builds the endpoints
+// clients can use
+func resolveEndpoints(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) ([]v1alpha1.EndpointStatus, error) {
+	service, err := kubernetes.GetService(ctx, r.client, vdb.ObjectMeta.Name, vdb.ObjectMeta.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	external := map[string]string{}
+
+	// LoadBalancer or NodePort service
+	if ext, err := kubernetes.GetService(ctx, r.client, vdb.ObjectMeta.Name+"-external", vdb.ObjectMeta.Namespace); err == nil {
+		for k, v := range externalServiceAddresses(*ext, nodeAddress(ctx, vdb, r)) {
+			external[k] = v
+		}
+	}
+
+	// passthrough routes take precedence over the external service
+	for _, port := range passthroughPorts(vdb) {
+		route := &oroutev1.Route{}
+		key := types.NamespacedName{Name: service.Name + "-" + port.Name, Namespace: vdb.ObjectMeta.Namespace}
+		if err := r.client.Get(ctx, key, route); err == nil && route.Spec.Host != "" {
+			external[port.Name] = route.Spec.Host + ":443"
+		}
+	}
+
+	return buildEndpoints(vdb, *service, external, odataURL(ctx, vdb, r)), nil
+}
+
+// odataURL returns the URL the odata service is exposed at through the Route, Ingress or Gateway
+func odataURL(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) string {
+	key := types.NamespacedName{Name: vdb.ObjectMeta.Name, Namespace: vdb.ObjectMeta.Namespace}
+
+	route := &oroutev1.Route{}
+	if err := r.client.Get(ctx, key, route); err == nil && route.Spec.Host != "" {
+		return fmt.Sprintf("https://%s/odata", route.Spec.Host)
+	}
+
+	ingress := &networkingv1beta1.Ingress{}
+	err := r.client.Get(ctx, key, ingress)
+	if err == nil {
+		return ingressURL(vdb, ingress.Status.LoadBalancer)
+	} else if meta.IsNoMatchError(err) {
+		extIngress := &extv1beta1.Ingress{}
+		if err := r.client.Get(ctx, key, extIngress); err == nil {
+			return ingressURL(vdb, extIngress.Status.LoadBalancer)
+		}
+	}
+
+	for _, exposeType := range vdb.Spec.Expose {
+		if exposeType == v1alpha1.Gateway && vdb.Spec.ExposeOptions != nil && vdb.Spec.ExposeOptions.Gateway != nil {
+			url, err := gatewayURL(ctx, vdb, r)
+			if err == nil {
+				return url
+			}
+		}
+	}
+	return ""
+}
+
+// nodeAddress returns the address of a node that runs the vdb, NodePort services are reachable through it
+func nodeAddress(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) string {
+	pods := &corev1.PodList{}
+	err := r.client.List(ctx, pods, client.InNamespace(vdb.ObjectMeta.Namespace), client.MatchingLabels(matchLabels(vdb.ObjectMeta.Name)))
+	if err != nil {
+		return ""
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.HostIP != "" {
+			return pod.Status.HostIP
+		}
+	}
+	return ""
+}
+
+// externalServiceAddresses returns host:port of each port of the LoadBalancer or NodePort service
+func externalServiceAddresses(service corev1.Service, nodeAddress string) map[string]string {
+	addresses := map[string]string{}
+	for _, port := range service.Spec.Ports {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				host := ingress.Hostname
+				if host == "" {
+					host = ingress.IP
+				}
+				if host != "" {
+					addresses[port.Name] = fmt.Sprintf("%s:%d", host, port.Port)
+					break
+				}
+			}
+		} else if service.Spec.Type == corev1.ServiceTypeNodePort && nodeAddress != "" && port.NodePort != 0 {
+			addresses[port.Name] = fmt.Sprintf("%s:%d", nodeAddress, port.NodePort)
+		}
+	}
+	return addresses
+}
+
+// buildEndpoints builds the endpoints from the ports of the vdb service, external holds host:port by port name
+func buildEndpoints(vdb *v1alpha1.VirtualDatabase, service corev1.Service, external map[string]string, odataURL string) []v1alpha1.EndpointStatus {
+	host := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	endpoints := []v1alpha1.EndpointStatus{}
+	for _, port := range service.Spec.Ports {
+		ep := v1alpha1.EndpointStatus{}
+		scheme := ""
+		path := ""
+		switch port.Name {
+		case "http":
+			ep.Protocol, scheme, path = v1alpha1.ODataProtocol, "http", "/odata"
+		case "https":
+			ep.Protocol, scheme, path, ep.TLS = v1alpha1.ODataProtocol, "https", "/odata", true
+		case "prometheus":
+			ep.Protocol, scheme, path = v1alpha1.MetricsProtocol, "http", "/metrics"
+		case "teiid":
+			ep.Protocol, scheme = v1alpha1.JDBCProtocol, "mm"
+		case "teiid-secure":
+			ep.Protocol, scheme, ep.TLS = v1alpha1.JDBCProtocol, "mms", true
+		case "pg":
+			ep.Protocol, scheme = v1alpha1.PostgresProtocol, "postgresql"
+		case "pg-secure":
+			ep.Protocol, scheme, ep.TLS = v1alpha1.PostgresProtocol, "postgresql", true
+		default:
+			continue
+		}
+		ep.InternalURL = fmt.Sprintf("%s://%s:%d%s", scheme, host, port.Port, path)
+		if address, ok := external[port.Name]; ok {
+			ep.ExternalURL = fmt.Sprintf("%s://%s%s", scheme, address, path)
+		}
+		if ep.Protocol == v1alpha1.ODataProtocol {
+			ep.ExternalURL = odataURL
+			if len(vdb.Spec.Build.Source.OpenAPI) > 0 {
+				endpoints = append(endpoints, openAPIEndpoint(ep))
+			}
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints
+}
+
+func openAPIEndpoint(odata v1alpha1.EndpointStatus) v1alpha1.EndpointStatus {
+	ep := v1alpha1.EndpointStatus{
+		Protocol:    v1alpha1.OpenAPIProtocol,
+		InternalURL: odata.InternalURL[:len(odata.InternalURL)-len("/odata")] + "/openapi.json",
+		TLS:         odata.TLS,
+	}
+	if odata.ExternalURL != "" {
+		ep.ExternalURL = odata.ExternalURL[:len(odata.ExternalURL)-len("/odata")] + "/openapi.json"
+	}
+	return ep
+}
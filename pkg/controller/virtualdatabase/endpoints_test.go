@@ -0,0 +1,105 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func servicePorts(ports []corev1.ContainerPort) []corev1.ServicePort {
+	servicePorts := []corev1.ServicePort{}
+	for _, port := range ports {
+		servicePorts = append(servicePorts, corev1.ServicePort{Name: port.Name, Port: getExposedPort(port)})
+	}
+	return servicePorts
+}
+
+func findEndpoint(endpoints []v1alpha1.EndpointStatus, protocol v1alpha1.EndpointProtocol, tls bool) *v1alpha1.EndpointStatus {
+	for i := range endpoints {
+		if endpoints[i].Protocol == protocol && endpoints[i].TLS == tls {
+			return &endpoints[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildEndpoints(t *testing.T) {
+	vdb := testVdb()
+	service := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "dv", Namespace: "myproject"},
+		Spec:       corev1.ServiceSpec{Ports: servicePorts(containerPorts(vdb, false))},
+	}
+
+	external := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "dv-external", Namespace: "myproject"},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeLoadBalancer,
+			Ports: servicePorts(containerPorts(vdb, true)),
+		},
+	}
+	assert.Equal(t, 0, len(externalServiceAddresses(external, "")))
+	external.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	addresses := externalServiceAddresses(external, "")
+	assert.Equal(t, "10.0.0.1:31443", addresses["teiid-secure"])
+	assert.Equal(t, "10.0.0.1:5433", addresses["pg-secure"])
+
+	endpoints := buildEndpoints(vdb, service, addresses, "https://dv.apps.example.com/odata")
+	assert.Equal(t, 6, len(endpoints))
+
+	odata := findEndpoint(endpoints, v1alpha1.ODataProtocol, false)
+	assert.Equal(t, "http://dv.myproject.svc:8080/odata", odata.InternalURL)
+	assert.Equal(t, "https://dv.apps.example.com/odata", odata.ExternalURL)
+
+	jdbc := findEndpoint(endpoints, v1alpha1.JDBCProtocol, false)
+	assert.Equal(t, "mm://dv.myproject.svc:31000", jdbc.InternalURL)
+	assert.Equal(t, "", jdbc.ExternalURL)
+
+	jdbcSecure := findEndpoint(endpoints, v1alpha1.JDBCProtocol, true)
+	assert.Equal(t, "mms://dv.myproject.svc:31443", jdbcSecure.InternalURL)
+	assert.Equal(t, "mms://10.0.0.1:31443", jdbcSecure.ExternalURL)
+
+	pgSecure := findEndpoint(endpoints, v1alpha1.PostgresProtocol, true)
+	assert.Equal(t, "postgresql://dv.myproject.svc:5433", pgSecure.InternalURL)
+	assert.Equal(t, "postgresql://10.0.0.1:5433", pgSecure.ExternalURL)
+
+	metrics := findEndpoint(endpoints, v1alpha1.MetricsProtocol, false)
+	assert.Equal(t, "http://dv.myproject.svc:9779/metrics", metrics.InternalURL)
+
+	assert.Nil(t, findEndpoint(endpoints, v1alpha1.OpenAPIProtocol, false))
+	vdb.Spec.Build.Source.OpenAPI = "{}"
+	endpoints = buildEndpoints(vdb, service, addresses, "https://dv.apps.example.com/odata")
+	openapi := findEndpoint(endpoints, v1alpha1.OpenAPIProtocol, false)
+	assert.Equal(t, "http://dv.myproject.svc:8080/openapi.json", openapi.InternalURL)
+	assert.Equal(t, "https://dv.apps.example.com/openapi.json", openapi.ExternalURL)
+}
+
+func TestNodePortAddresses(t *testing.T) {
+	external := corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: "teiid-secure", Port: 31443, NodePort: 30443}},
+		},
+	}
+	assert.Equal(t, 0, len(externalServiceAddresses(external, "")))
+	assert.Equal(t, "192.168.0.10:30443", externalServiceAddresses(external, "192.168.0.10")["teiid-secure"])
+}
@@ -0,0 +1,28 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testVdb is the Virtual Database the tests start from, each test sets the spec fields it exercises
+func testVdb() *v1alpha1.VirtualDatabase {
+	return &v1alpha1.VirtualDatabase{ObjectMeta: metav1.ObjectMeta{Name: "dv", Namespace: "myproject"}}
+}
@@ -47,7 +47,11 @@ func (action *initializeAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bool {
 // Handle handles the virtualdatabase
 func (action *initializeAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
 	// build digest the vdb/config contents
-	digest, err := ComputeForVirtualDatabase(vdb)
+	factories, err := connectionFactories(ctx, r, vdb.ObjectMeta.Namespace)
+	if err != nil {
+		return err
+	}
+	digest, err := ComputeForVirtualDatabase(vdb, factories)
 	if err != nil {
 		return err
 	}
@@ -74,6 +78,48 @@ func (action *initializeAction) Handle(ctx context.Context, vdb *v1alpha1.Virtua
 			}
 		}
 
+		// make sure the secrets for the security configuration exist
+		if !oidcSecretsExist(ctx, r.client, vdb) {
+			vdb.Status.Failure = "Security configuration missing, make sure to supply the Secrets referenced in spec.security.oidc"
+			return nil
+		}
+
+		if err := validateProtocols(vdb); err != nil {
+			vdb.Status.Failure = err.Error()
+			return nil
+		}
+
+		if err := validateDataRoles(vdb); err != nil {
+			vdb.Status.Failure = err.Error()
+			return nil
+		}
+
+		if err := validateBufferStorage(vdb); err != nil {
+			vdb.Status.Failure = err.Error()
+			return nil
+		}
+
+		if err := validateRollout(vdb); err != nil {
+			vdb.Status.Failure = err.Error()
+			return nil
+		}
+
+		if _, err := computingResources(ctx, vdb, r); err != nil {
+			vdb.Status.Failure = err.Error()
+			return nil
+		}
+
+		repos, err := dataSourceRepositories(ctx, vdb, r)
+		if err != nil {
+			return err
+		}
+		dataSources, err := resolveDataSources(vdb, repos)
+		if err != nil {
+			vdb.Status.Failure = err.Error()
+			return nil
+		}
+		vdb.Status.DataSources = dataSources
+
 		// initialize with defaults
 		vdb.Status.Failure = ""
 		vdb.Status.Phase = v1alpha1.ReconcilerPhaseCreateCacheStore
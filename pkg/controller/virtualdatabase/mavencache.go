@@ -0,0 +1,243 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	mavenCacheName     = "teiid-maven-cache"
+	mavenCachePort     = 8081
+	mavenCacheDataDir  = "/nexus-data"
+	defaultMirrorOf    = "central"
+	defaultCacheImage  = "docker.io/sonatype/nexus3:3.30.1"
+	defaultCacheSize   = "10Gi"
+	mavenCacheJVMFlags = "-Xms1g -Xmx1g -XX:MaxDirectMemorySize=1g -Djava.util.prefs.userRoot=/nexus-data/javaprefs"
+)
+
+// mavenMirror is the mirror of the generated settings.xml the builds download the Maven artifacts through, nil
+// when no Maven cache is configured
+func mavenMirror(vdb *v1alpha1.VirtualDatabase) *maven.Mirror {
+	mc := constants.Config.MavenCache
+	url := mc.URL
+	if url == "" && mc.Deploy {
+		url = fmt.Sprintf("http://%s.%s.svc:%d/repository/maven-public/", mavenCacheName, builderNamespace(vdb), mavenCachePort)
+	}
+	if url == "" {
+		return nil
+	}
+	mirrorOf := mc.MirrorOf
+	if mirrorOf == "" {
+		mirrorOf = defaultMirrorOf
+	}
+	return &maven.Mirror{ID: mavenCacheName, Name: "Maven cache of the Virtual Database builds", URL: url, MirrorOf: mirrorOf}
+}
+
+// ensureMavenCache deploys the proxy repository in the builder namespace when configured, returns true once it
+// serves requests
+func ensureMavenCache(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (bool, error) {
+	if !constants.Config.MavenCache.Deploy || constants.Config.MavenCache.URL != "" {
+		return true, nil
+	}
+	namespace := builderNamespace(vdb)
+	api := builderNamespaceAPI(r)
+
+	claim, err := buildMavenCacheClaim(namespace)
+	if err != nil {
+		return false, err
+	}
+	claims := api.CoreV1().PersistentVolumeClaims(namespace)
+	if _, err = claims.Get(claim.Name, metav1.GetOptions{}); k8serrors.IsNotFound(err) {
+		if _, err = claims.Create(&claim); err != nil {
+			return false, err
+		}
+		log.Info("Maven cache PersistentVolumeClaim created:", namespace, "/", claim.Name)
+	} else if err != nil {
+		return false, err
+	}
+
+	service := buildMavenCacheService(namespace)
+	services := api.CoreV1().Services(namespace)
+	if _, err = services.Get(service.Name, metav1.GetOptions{}); k8serrors.IsNotFound(err) {
+		if _, err = services.Create(&service); err != nil {
+			return false, err
+		}
+		log.Info("Maven cache Service created:", namespace, "/", service.Name)
+	} else if err != nil {
+		return false, err
+	}
+
+	dc := buildMavenCacheDeployment(namespace)
+	deployments := api.AppsV1().Deployments(namespace)
+	existing, err := deployments.Get(dc.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		if _, err = deployments.Create(&dc); err != nil {
+			return false, err
+		}
+		log.Info("Maven cache Deployment created:", namespace, "/", dc.Name)
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return existing.Status.ReadyReplicas > 0, nil
+}
+
+func mavenCacheLabels() map[string]string {
+	return map[string]string{
+		"app": mavenCacheName,
+	}
+}
+
+func buildMavenCacheClaim(namespace string) (corev1.PersistentVolumeClaim, error) {
+	mc := constants.Config.MavenCache
+	size := mc.Size
+	if size == "" {
+		size = defaultCacheSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return corev1.PersistentVolumeClaim{}, fmt.Errorf("invalid mavenCache.size %q in the operator configuration: %v", size, err)
+	}
+
+	pvc := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mavenCacheName,
+			Namespace: namespace,
+			Labels:    mavenCacheLabels(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+			},
+		},
+	}
+	if mc.StorageClass != "" {
+		pvc.Spec.StorageClassName = &mc.StorageClass
+	}
+	pvc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+	return pvc, nil
+}
+
+func buildMavenCacheService(namespace string) corev1.Service {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mavenCacheName,
+			Namespace: namespace,
+			Labels:    mavenCacheLabels(),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: mavenCacheLabels(),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       mavenCachePort,
+					TargetPort: intstr.FromInt(mavenCachePort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+	svc.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+	return svc
+}
+
+func buildMavenCacheDeployment(namespace string) appsv1.Deployment {
+	image := constants.Config.MavenCache.Image
+	if image == "" {
+		image = defaultCacheImage
+	}
+	replicas := int32(1)
+
+	dc := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mavenCacheName,
+			Namespace: namespace,
+			Labels:    mavenCacheLabels(),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			// the volume can only be mounted by one pod at a time
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Selector: &metav1.LabelSelector{MatchLabels: mavenCacheLabels()},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: mavenCacheLabels(),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  mavenCacheName,
+							Image: image,
+							Env: []corev1.EnvVar{
+								{Name: "INSTALL4J_ADD_VM_PARAMS", Value: mavenCacheJVMFlags},
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "http", ContainerPort: mavenCachePort, Protocol: corev1.ProtocolTCP},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("1Gi"),
+									corev1.ResourceCPU:    resource.MustParse("200m"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("3Gi"),
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/service/rest/v1/status",
+										Port: intstr.FromInt(mavenCachePort),
+									},
+								},
+								InitialDelaySeconds: 30,
+								PeriodSeconds:       10,
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "data", MountPath: mavenCacheDataDir},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: mavenCacheName},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	dc.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	return dc
+}
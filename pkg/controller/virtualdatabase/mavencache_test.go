@@ -0,0 +1,80 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMavenMirror(t *testing.T) {
+	vdb := testVdb()
+	config := constants.Config
+	defer func() { constants.Config = config }()
+
+	constants.Config.MavenCache = conf.MavenCache{}
+	assert.Nil(t, mavenMirror(vdb))
+
+	constants.Config.MavenCache = conf.MavenCache{URL: "https://nexus.example.com/repository/maven-public/", MirrorOf: "*"}
+	mirror := mavenMirror(vdb)
+	assert.Equal(t, "https://nexus.example.com/repository/maven-public/", mirror.URL)
+	assert.Equal(t, "*", mirror.MirrorOf)
+
+	constants.Config.MavenCache = conf.MavenCache{Deploy: true}
+	constants.Config.BuilderNamespace = "teiid-builder"
+	mirror = mavenMirror(vdb)
+	assert.Equal(t, "http://teiid-maven-cache.teiid-builder.svc:8081/repository/maven-public/", mirror.URL)
+	assert.Equal(t, "central", mirror.MirrorOf)
+
+	settings := maven.NewDefaultSettings([]maven.Repository{})
+	settings.Mirrors = &maven.Mirrors{Mirror: []maven.Mirror{*mirror}}
+	content, err := maven.EncodeXML(settings)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(content, "<mirrorOf>central</mirrorOf>"))
+}
+
+func TestBuildMavenCache(t *testing.T) {
+	config := constants.Config
+	defer func() { constants.Config = config }()
+	constants.Config.MavenCache = conf.MavenCache{Deploy: true, Size: "20Gi", StorageClass: "fast"}
+
+	claim, err := buildMavenCacheClaim("teiid-builder")
+	assert.Nil(t, err)
+	assert.Equal(t, "teiid-maven-cache", claim.Name)
+	assert.Equal(t, "teiid-builder", claim.Namespace)
+	size := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Equal(t, "20Gi", size.String())
+	assert.Equal(t, "fast", *claim.Spec.StorageClassName)
+
+	dc := buildMavenCacheDeployment("teiid-builder")
+	assert.Equal(t, appsv1.RecreateDeploymentStrategyType, dc.Spec.Strategy.Type)
+	assert.Equal(t, defaultCacheImage, dc.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "teiid-maven-cache", dc.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.Equal(t, dc.Spec.Selector.MatchLabels, buildMavenCacheService("teiid-builder").Spec.Selector)
+
+	constants.Config.MavenCache.Size = "lots"
+	_, err = buildMavenCacheClaim("teiid-builder")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,442 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// policyGroupLabel label OpenShift puts on the namespaces of the ingress routers and monitoring stack
+	policyGroupLabel = "network.openshift.io/policy-group"
+	// egressSourceAnnotation annotation on the NetworkPolicy holding the digest of the spec its egress rules were
+	// resolved from
+	egressSourceAnnotation = "teiid.io/egress-source"
+)
+
+// jdbcHostRegex captures the host list of a jdbc url, jdbc:postgresql://host:port/db, jdbc:oracle:thin:@host:port:sid
+var jdbcHostRegex = regexp.MustCompile(`^jdbc:([a-zA-Z0-9]+)[^/@]*(?://|@//|@)([^/;?:,]+(?::\d+)?(?:,[^/;?:,]+(?::\d+)?)*)`)
+
+// default ports of the databases when not given in the jdbc url
+var jdbcDefaultPorts = map[string]int32{
+	"postgresql": 5432,
+	"mysql":      3306,
+	"mariadb":    3306,
+	"sqlserver":  1433,
+	"oracle":     1521,
+	"db2":        50000,
+	"teiid":      31000,
+}
+
+// lookupIP resolves the host names of the data sources, replaced in tests
+var lookupIP = net.LookupIP
+
+// NewNetworkPolicyAction creates a new network policy action
+func NewNetworkPolicyAction() Action {
+	return &networkPolicyAction{}
+}
+
+type networkPolicyAction struct {
+	baseAction
+}
+
+// Name returns a common name of the action
+func (action *networkPolicyAction) Name() string {
+	return "NetworkPolicyAction"
+}
+
+// CanHandle tells whether this action can handle the virtualdatabase
+func (action *networkPolicyAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Status.Phase == v1alpha1.ReconcilerPhaseServiceCreated ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseKeystoreCreated ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying ||
+		vdb.Status.Phase == v1alpha1.ReconcilerPhaseRunning
+}
+
+// Handle handles the virtualdatabase, the NetworkPolicy follows spec.networkPolicy and spec.expose and is
+// removed when spec.networkPolicy is omitted
+func (action *networkPolicyAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	if vdb.Spec.NetworkPolicy == nil {
+		return action.removeNetworkPolicy(ctx, vdb, r)
+	}
+
+	source, err := egressSource(vdb)
+	if err != nil {
+		return err
+	}
+	egress := []networkingv1.NetworkPolicyEgressRule{}
+	if vdb.Spec.NetworkPolicy.Egress {
+		existing := &networkingv1.NetworkPolicy{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: vdb.ObjectMeta.Name, Namespace: vdb.ObjectMeta.Namespace}, existing)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && existing.Annotations[egressSourceAnnotation] == source {
+			// the host names are resolved when the spec changes, not on every pass
+			egress = existing.Spec.Egress
+		} else {
+			egress = egressRules(vdb, egressTargets(ctx, vdb, r))
+		}
+	}
+	desired := buildNetworkPolicy(vdb, exposeTypes(vdb, r), egress)
+	desired.Annotations = map[string]string{egressSourceAnnotation: source}
+
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.client, policy, func() error {
+		mergeMetadata(&policy.ObjectMeta, desired.ObjectMeta)
+		policy.Spec = desired.Spec
+		return controllerutil.SetControllerReference(vdb, policy, r.client.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("NetworkPolicy ", result, ":", policy.Name)
+	}
+	return nil
+}
+
+func (action *networkPolicyAction) removeNetworkPolicy(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	policy := &networkingv1.NetworkPolicy{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: vdb.ObjectMeta.Name, Namespace: vdb.ObjectMeta.Namespace}, policy)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(policy, vdb) {
+		return nil
+	}
+	if err = r.client.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	log.Info("NetworkPolicy removed:", policy.Name)
+	return nil
+}
+
+// operatorPeer selects the operator pods, the operator runs in the watched namespace and OPERATOR_NAME carries the
+// name label of its pod
+func operatorPeer() []networkingv1.NetworkPolicyPeer {
+	name := os.Getenv("OPERATOR_NAME")
+	if name == "" {
+		name = "teiid-operator"
+	}
+	return []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{"name": name},
+	}}}
+}
+
+// buildNetworkPolicy returns the NetworkPolicy of the vdb pods, data ports are open to the configured peers and
+// the routers or controllers of the requested exposure, metrics to the monitoring namespaces, jolokia only when peers are given
+// and the OData port to the operator when it verifies new versions
+func buildNetworkPolicy(vdb *v1alpha1.VirtualDatabase, exposeTypes []v1alpha1.ExposeType,
+	egress []networkingv1.NetworkPolicyEgressRule) networkingv1.NetworkPolicy {
+
+	spec := vdb.Spec.NetworkPolicy
+
+	dataPorts := []networkingv1.NetworkPolicyPort{}
+	securePorts := []networkingv1.NetworkPolicyPort{}
+	var httpPort, metricsPort, jolokiaPort, passthroughPort []networkingv1.NetworkPolicyPort
+	for _, port := range containerPorts(vdb, false) {
+		policyPort := []networkingv1.NetworkPolicyPort{networkPolicyPort(port.ContainerPort, corev1.ProtocolTCP)}
+		switch port.Name {
+		case "jolokia":
+			jolokiaPort = policyPort
+		case "prometheus":
+			metricsPort = policyPort
+		case "http", "https":
+			// http only serves the health checks unless OData is served on it
+			if port.ContainerPort != odataPort(vdb) || !isODataExposed(vdb) {
+				continue
+			}
+			httpPort = policyPort
+			dataPorts = append(dataPorts, policyPort...)
+		case "teiid-secure", "pg-secure":
+			if port.Name == "teiid-secure" {
+				passthroughPort = policyPort
+			}
+			securePorts = append(securePorts, policyPort...)
+			dataPorts = append(dataPorts, policyPort...)
+		default:
+			dataPorts = append(dataPorts, policyPort...)
+		}
+	}
+
+	from := spec.From
+	if len(from) == 0 {
+		from = []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	}
+	ingress := []networkingv1.NetworkPolicyIngressRule{}
+	// a rule without ports opens all of them, skip rules of the disabled protocols
+	addRule := func(ports []networkingv1.NetworkPolicyPort, from []networkingv1.NetworkPolicyPeer) {
+		if len(ports) > 0 {
+			ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{Ports: ports, From: from})
+		}
+	}
+	addRule(dataPorts, from)
+
+	routers := spec.IngressControllers
+	if len(routers) == 0 {
+		routers = []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{policyGroupLabel: "ingress"},
+		}}}
+	}
+	controllers := spec.IngressControllers
+	if len(controllers) == 0 {
+		controllers = []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}
+	}
+	for _, exposeType := range exposeTypes {
+		switch exposeType {
+		case v1alpha1.Route:
+			addRule(httpPort, routers)
+		case v1alpha1.PassthroughRoute:
+			addRule(passthroughPort, routers)
+		case v1alpha1.Ingress:
+			addRule(httpPort, controllers)
+		case v1alpha1.Gateway:
+			addRule(httpPort, controllers)
+			addRule(passthroughPort, controllers)
+		case v1alpha1.LoadBalancer, v1alpha1.NodePort:
+			// clients outside of the cluster, no restriction on the source
+			addRule(securePorts, nil)
+		}
+	}
+
+	metrics := spec.Metrics
+	if len(metrics) == 0 {
+		metrics = []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{policyGroupLabel: "monitoring"},
+		}}}
+	}
+	addRule(metricsPort, metrics)
+
+	if len(spec.Jolokia) > 0 {
+		addRule(jolokiaPort, spec.Jolokia)
+	}
+
+	// the operator queries a pod of the deploying version directly to verify it
+	if isVersioned(vdb) && vdb.Spec.Rollout.BlueGreen != nil && vdb.Spec.Rollout.BlueGreen.VerificationPath != "" {
+		addRule([]networkingv1.NetworkPolicyPort{networkPolicyPort(odataPort(vdb), corev1.ProtocolTCP)}, operatorPeer())
+	}
+
+	policy := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vdb.ObjectMeta.Name,
+			Namespace: vdb.ObjectMeta.Namespace,
+			Labels: map[string]string{
+				"app":                      vdb.ObjectMeta.Name,
+				"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name,
+				"teiid.io/type":            "VirtualDatabase",
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: matchLabels(vdb.ObjectMeta.Name)},
+			Ingress:     ingress,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+
+	if spec.Egress {
+		policy.Spec.Egress = egress
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+	}
+	policy.SetGroupVersionKind(networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+	return policy
+}
+
+func networkPolicyPort(port int32, protocol corev1.Protocol) networkingv1.NetworkPolicyPort {
+	p := intstr.FromInt(int(port))
+	return networkingv1.NetworkPolicyPort{Port: &p, Protocol: &protocol}
+}
+
+// egressSource digest of the parts of the vdb the egress rules are resolved from, the data sources, the OIDC issuer
+// and the ConfigMaps and Secrets the jdbc-url is read from
+func egressSource(vdb *v1alpha1.VirtualDatabase) (string, error) {
+	data, err := json.Marshal(struct {
+		NetworkPolicy *v1alpha1.NetworkPolicyObject
+		DataSources   []v1alpha1.DataSourceObject
+		Security      *v1alpha1.SecurityObject
+		ConfigDigest  string
+	}{vdb.Spec.NetworkPolicy, vdb.Spec.DataSources, vdb.Spec.Security, vdb.Status.ConfigDigest})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+// egressTarget host and port the vdb connects to, port is zero when not known
+type egressTarget struct {
+	Host string
+	Port int32
+}
+
+// egressTargets returns the hosts of the jdbc-url of the data sources and the OIDC issuer
+func egressTargets(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) []egressTarget {
+	targets := []egressTarget{}
+	for _, ds := range vdb.Spec.DataSources {
+		for _, env := range ds.Properties {
+			if !strings.EqualFold(env.Name, "jdbc-url") {
+				continue
+			}
+			value := env.Value
+			if env.ValueFrom != nil {
+				var err error
+				if env.ValueFrom.SecretKeyRef != nil {
+					value, err = kubernetes.GetSecretRefValue(ctx, r.client, vdb.ObjectMeta.Namespace, env.ValueFrom.SecretKeyRef)
+				} else if env.ValueFrom.ConfigMapKeyRef != nil {
+					value, err = kubernetes.GetConfigMapRefValue(ctx, r.client, vdb.ObjectMeta.Namespace, env.ValueFrom.ConfigMapKeyRef)
+				}
+				if err != nil {
+					log.Warn("Failed to read the jdbc-url of data source ", ds.Name, ", it is not added to the NetworkPolicy. ", err)
+					continue
+				}
+			}
+			targets = append(targets, jdbcTargets(value)...)
+		}
+	}
+
+	if isOIDCEnabled(vdb) {
+		if u, err := url.Parse(vdb.Spec.Security.OIDC.IssuerURL); err == nil && u.Hostname() != "" {
+			port := int32(443)
+			if u.Scheme == "http" {
+				port = 80
+			}
+			if p, err := strconv.Atoi(u.Port()); err == nil {
+				port = int32(p)
+			}
+			targets = append(targets, egressTarget{Host: u.Hostname(), Port: port})
+		}
+	}
+	return targets
+}
+
+// jdbcTargets parses the hosts and ports out of a jdbc url
+func jdbcTargets(jdbcURL string) []egressTarget {
+	match := jdbcHostRegex.FindStringSubmatch(strings.TrimSpace(jdbcURL))
+	if match == nil {
+		return []egressTarget{}
+	}
+	defaultPort := jdbcDefaultPorts[strings.ToLower(match[1])]
+	targets := []egressTarget{}
+	for _, hostPort := range strings.Split(match[2], ",") {
+		target := egressTarget{Host: hostPort, Port: defaultPort}
+		if idx := strings.LastIndex(hostPort, ":"); idx != -1 {
+			target.Host = hostPort[:idx]
+			if p, err := strconv.Atoi(hostPort[idx+1:]); err == nil {
+				target.Port = int32(p)
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// egressRules returns egress to DNS, the pods in the same namespace, the targets and the additional rules.
+// Services of other namespaces are matched on the namespace, other hosts are resolved to IP addresses
+func egressRules(vdb *v1alpha1.VirtualDatabase, targets []egressTarget) []networkingv1.NetworkPolicyEgressRule {
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				networkPolicyPort(53, corev1.ProtocolUDP),
+				networkPolicyPort(53, corev1.ProtocolTCP),
+				networkPolicyPort(5353, corev1.ProtocolUDP),
+				networkPolicyPort(5353, corev1.ProtocolTCP),
+			},
+		},
+		{
+			To: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+		},
+	}
+
+	for _, target := range targets {
+		peers := egressPeers(target.Host)
+		if len(peers) == 0 {
+			continue
+		}
+		rule := networkingv1.NetworkPolicyEgressRule{To: peers}
+		if target.Port != 0 {
+			rule.Ports = []networkingv1.NetworkPolicyPort{networkPolicyPort(target.Port, corev1.ProtocolTCP)}
+		}
+		rules = append(rules, rule)
+	}
+
+	if vdb.Spec.NetworkPolicy != nil {
+		rules = append(rules, vdb.Spec.NetworkPolicy.AdditionalEgress...)
+	}
+	return rules
+}
+
+func egressPeers(host string) []networkingv1.NetworkPolicyPeer {
+	if ip := net.ParseIP(host); ip != nil {
+		return []networkingv1.NetworkPolicyPeer{ipPeer(ip)}
+	}
+
+	// services, <name>.<namespace>.svc[.cluster.local], a single label is in the same namespace
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(labels) == 1 {
+		return []networkingv1.NetworkPolicyPeer{}
+	}
+	if len(labels) >= 3 && labels[2] == "svc" {
+		return []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": labels[1]},
+		}}}
+	}
+
+	ips, err := lookupIP(host)
+	if err != nil {
+		log.Warn("Failed to resolve ", host, ", it is not added to the NetworkPolicy. ", err)
+		return []networkingv1.NetworkPolicyPeer{}
+	}
+	// resolvers rotate the addresses, keep the order stable so the policy is not updated on every pass
+	sort.Slice(ips, func(i, j int) bool { return ips[i].String() < ips[j].String() })
+	peers := []networkingv1.NetworkPolicyPeer{}
+	for _, ip := range ips {
+		peers = append(peers, ipPeer(ip))
+	}
+	return peers
+}
+
+func ipPeer(ip net.IP) networkingv1.NetworkPolicyPeer {
+	cidr := ip.String() + "/32"
+	if ip.To4() == nil {
+		cidr = ip.String() + "/128"
+	}
+	return networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}}
+}
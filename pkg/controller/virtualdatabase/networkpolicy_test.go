@@ -0,0 +1,175 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJdbcTargets(t *testing.T) {
+	assert.Equal(t, []egressTarget{{Host: "localhost", Port: 5432}},
+		jdbcTargets("jdbc:postgresql://localhost:5432/sampledb"))
+	assert.Equal(t, []egressTarget{{Host: "postgresql.db.svc", Port: 5432}},
+		jdbcTargets("jdbc:postgresql://postgresql.db.svc/sampledb"))
+	assert.Equal(t, []egressTarget{{Host: "db1", Port: 3306}, {Host: "db2", Port: 3307}},
+		jdbcTargets("jdbc:mysql://db1,db2:3307/sampledb?useSSL=false"))
+	assert.Equal(t, []egressTarget{{Host: "10.0.0.5", Port: 1521}},
+		jdbcTargets("jdbc:oracle:thin:@10.0.0.5:1521:XE"))
+	assert.Equal(t, []egressTarget{{Host: "oracle.example.com", Port: 1522}},
+		jdbcTargets("jdbc:oracle:thin:@//oracle.example.com:1522/service"))
+	assert.Equal(t, []egressTarget{{Host: "mssql", Port: 1433}},
+		jdbcTargets("jdbc:sqlserver://mssql;databaseName=sample"))
+	assert.Equal(t, 0, len(jdbcTargets("mongodb://localhost")))
+}
+
+func TestBuildNetworkPolicy(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.NetworkPolicy = &v1alpha1.NetworkPolicyObject{}
+
+	policy := buildNetworkPolicy(vdb, []v1alpha1.ExposeType{v1alpha1.Route}, nil)
+	assert.Equal(t, "dv", policy.Name)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+	// data ports, route and metrics, no jolokia
+	assert.Equal(t, 3, len(policy.Spec.Ingress))
+	assert.Equal(t, 5, len(policy.Spec.Ingress[0].Ports))
+	assert.NotNil(t, policy.Spec.Ingress[0].From[0].PodSelector)
+	assert.Equal(t, 8080, policy.Spec.Ingress[1].Ports[0].Port.IntValue())
+	assert.Equal(t, "ingress", policy.Spec.Ingress[1].From[0].NamespaceSelector.MatchLabels[policyGroupLabel])
+	assert.Equal(t, 9779, policy.Spec.Ingress[2].Ports[0].Port.IntValue())
+	assert.Equal(t, "monitoring", policy.Spec.Ingress[2].From[0].NamespaceSelector.MatchLabels[policyGroupLabel])
+	for _, rule := range policy.Spec.Ingress {
+		for _, port := range rule.Ports {
+			assert.NotEqual(t, 8778, port.Port.IntValue())
+		}
+	}
+
+	apps := networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{"name": "apps"},
+	}}
+	vdb.Spec.NetworkPolicy.From = []networkingv1.NetworkPolicyPeer{apps}
+	vdb.Spec.NetworkPolicy.Jolokia = []networkingv1.NetworkPolicyPeer{apps}
+	vdb.Spec.NetworkPolicy.Egress = true
+	policy = buildNetworkPolicy(vdb, []v1alpha1.ExposeType{v1alpha1.LoadBalancer}, egressRules(vdb, nil))
+	assert.Equal(t, 4, len(policy.Spec.Ingress))
+	assert.Equal(t, apps, policy.Spec.Ingress[0].From[0])
+	assert.Equal(t, 2, len(policy.Spec.Ingress[1].Ports))
+	assert.Equal(t, 0, len(policy.Spec.Ingress[1].From))
+	assert.Equal(t, 8778, policy.Spec.Ingress[3].Ports[0].Port.IntValue())
+	assert.Equal(t, 2, len(policy.Spec.PolicyTypes))
+	assert.Equal(t, 2, len(policy.Spec.Egress))
+}
+
+func TestNetworkPolicyPassthroughRoute(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.NetworkPolicy = &v1alpha1.NetworkPolicyObject{}
+	policy := buildNetworkPolicy(vdb, []v1alpha1.ExposeType{v1alpha1.PassthroughRoute}, nil)
+	// data ports, passthrough route and metrics, pg-secure is not reachable through the router
+	assert.Equal(t, 3, len(policy.Spec.Ingress))
+	assert.Equal(t, 1, len(policy.Spec.Ingress[1].Ports))
+	assert.Equal(t, 31443, policy.Spec.Ingress[1].Ports[0].Port.IntValue())
+	assert.Equal(t, "ingress", policy.Spec.Ingress[1].From[0].NamespaceSelector.MatchLabels[policyGroupLabel])
+}
+
+func TestNetworkPolicyIngressControllers(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.NetworkPolicy = &v1alpha1.NetworkPolicyObject{}
+	policy := buildNetworkPolicy(vdb, []v1alpha1.ExposeType{v1alpha1.Ingress}, nil)
+	// data ports, ingress and metrics, controllers from any namespace
+	assert.Equal(t, 3, len(policy.Spec.Ingress))
+	assert.Equal(t, 8080, policy.Spec.Ingress[1].Ports[0].Port.IntValue())
+	assert.Equal(t, 0, len(policy.Spec.Ingress[1].From[0].NamespaceSelector.MatchLabels))
+
+	gateways := networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{"name": "gateways"},
+	}}
+	vdb.Spec.NetworkPolicy.IngressControllers = []networkingv1.NetworkPolicyPeer{gateways}
+	policy = buildNetworkPolicy(vdb, []v1alpha1.ExposeType{v1alpha1.Gateway, v1alpha1.Route}, nil)
+	// data ports, gateway http and tls, route and metrics
+	assert.Equal(t, 5, len(policy.Spec.Ingress))
+	assert.Equal(t, 8080, policy.Spec.Ingress[1].Ports[0].Port.IntValue())
+	assert.Equal(t, gateways, policy.Spec.Ingress[1].From[0])
+	assert.Equal(t, 31443, policy.Spec.Ingress[2].Ports[0].Port.IntValue())
+	assert.Equal(t, gateways, policy.Spec.Ingress[3].From[0])
+}
+
+func TestNetworkPolicyVerification(t *testing.T) {
+	vdb := blueGreenVdb()
+	vdb.Spec.NetworkPolicy = &v1alpha1.NetworkPolicyObject{}
+	policy := buildNetworkPolicy(vdb, nil, nil)
+	assert.Equal(t, 2, len(policy.Spec.Ingress))
+
+	vdb.Spec.Rollout.BlueGreen.VerificationPath = "/odata/portfolio/CustomerZip?$top=1"
+	policy = buildNetworkPolicy(vdb, nil, nil)
+	assert.Equal(t, 3, len(policy.Spec.Ingress))
+	assert.Equal(t, 8080, policy.Spec.Ingress[2].Ports[0].Port.IntValue())
+	assert.Equal(t, "teiid-operator", policy.Spec.Ingress[2].From[0].PodSelector.MatchLabels["name"])
+}
+
+func TestEgressRules(t *testing.T) {
+	lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.168.1.20"), net.ParseIP("192.168.1.10")}, nil
+	}
+	defer func() { lookupIP = net.LookupIP }()
+
+	vdb := testVdb()
+	vdb.Spec.NetworkPolicy = &v1alpha1.NetworkPolicyObject{Egress: true}
+	rules := egressRules(vdb, []egressTarget{
+		{Host: "postgresql", Port: 5432},
+		{Host: "postgresql.db.svc.cluster.local", Port: 5432},
+		{Host: "10.0.0.5", Port: 1521},
+		{Host: "keycloak.example.com", Port: 443},
+	})
+	// dns, same namespace, then the targets except the service in same namespace
+	assert.Equal(t, 5, len(rules))
+	assert.Equal(t, "db", rules[2].To[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+	assert.Equal(t, "10.0.0.5/32", rules[3].To[0].IPBlock.CIDR)
+	assert.Equal(t, 1521, rules[3].Ports[0].Port.IntValue())
+	assert.Equal(t, "192.168.1.10/32", rules[4].To[0].IPBlock.CIDR)
+	assert.Equal(t, "192.168.1.20/32", rules[4].To[1].IPBlock.CIDR)
+}
+
+func TestEgressSource(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.NetworkPolicy = &v1alpha1.NetworkPolicyObject{Egress: true}
+	vdb.Spec.DataSources = []v1alpha1.DataSourceObject{{
+		Name:       "sampledb",
+		Properties: []corev1.EnvVar{{Name: "jdbc-url", Value: "jdbc:postgresql://db.example.com/sampledb"}},
+	}}
+	source, err := egressSource(vdb)
+	assert.Nil(t, err)
+
+	// the hosts are not resolved again unless the spec or the configuration changes
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+	same, _ := egressSource(vdb)
+	assert.Equal(t, source, same)
+
+	vdb.Status.ConfigDigest = "c2"
+	changed, _ := egressSource(vdb)
+	assert.NotEqual(t, source, changed)
+
+	vdb.Spec.DataSources[0].Properties[0].Value = "jdbc:postgresql://db2.example.com/sampledb"
+	moved, _ := egressSource(vdb)
+	assert.NotEqual(t, changed, moved)
+}
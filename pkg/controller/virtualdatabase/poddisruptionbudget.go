@@ -0,0 +1,131 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"errors"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// NewPodDisruptionBudgetAction creates a new pod disruption budget action
+func NewPodDisruptionBudgetAction() Action {
+	return &podDisruptionBudgetAction{}
+}
+
+type podDisruptionBudgetAction struct {
+	baseAction
+}
+
+// Name returns a common name of the action
+func (action *podDisruptionBudgetAction) Name() string {
+	return "PodDisruptionBudgetAction"
+}
+
+// CanHandle tells whether this action can handle the virtualdatabase
+func (action *podDisruptionBudgetAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying || vdb.Status.Phase == v1alpha1.ReconcilerPhaseRunning
+}
+
+// Handle handles the virtualdatabase, the PodDisruptionBudget exists only while there is more than one replica
+func (action *podDisruptionBudgetAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	if vdb.Spec.Replicas == nil || *vdb.Spec.Replicas <= 1 {
+		return action.removePodDisruptionBudget(ctx, vdb, r)
+	}
+
+	desired, err := buildPodDisruptionBudget(vdb)
+	if err != nil {
+		vdb.Status.Failure = err.Error()
+		return err
+	}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.client, pdb, func() error {
+		mergeMetadata(&pdb.ObjectMeta, desired.ObjectMeta)
+		pdb.Spec = desired.Spec
+		return controllerutil.SetControllerReference(vdb, pdb, r.client.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("PodDisruptionBudget ", result, ":", pdb.Name)
+	}
+	return nil
+}
+
+func (action *podDisruptionBudgetAction) removePodDisruptionBudget(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) error {
+
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: vdb.ObjectMeta.Name, Namespace: vdb.ObjectMeta.Namespace}, pdb)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(pdb, vdb) {
+		return nil
+	}
+	if err = r.client.Delete(ctx, pdb); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	log.Info("PodDisruptionBudget removed:", pdb.Name)
+	return nil
+}
+
+// buildPodDisruptionBudget returns the PodDisruptionBudget of the vdb pods
+func buildPodDisruptionBudget(vdb *v1alpha1.VirtualDatabase) (policyv1beta1.PodDisruptionBudget, error) {
+	spec := policyv1beta1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: matchLabels(vdb.ObjectMeta.Name)},
+	}
+	if budget := vdb.Spec.DisruptionBudget; budget != nil && (budget.MinAvailable != nil || budget.MaxUnavailable != nil) {
+		if budget.MinAvailable != nil && budget.MaxUnavailable != nil {
+			return policyv1beta1.PodDisruptionBudget{},
+				errors.New("only one of minAvailable or maxUnavailable can be given in spec.disruptionBudget")
+		}
+		spec.MinAvailable = budget.MinAvailable
+		spec.MaxUnavailable = budget.MaxUnavailable
+	} else {
+		maxUnavailable := intstr.FromInt(1)
+		spec.MaxUnavailable = &maxUnavailable
+	}
+
+	pdb := policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vdb.ObjectMeta.Name,
+			Namespace: vdb.ObjectMeta.Namespace,
+			Labels: map[string]string{
+				"app":                      vdb.ObjectMeta.Name,
+				"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name,
+				"teiid.io/type":            "VirtualDatabase",
+			},
+		},
+		Spec: spec,
+	}
+	pdb.SetGroupVersionKind(policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+	return pdb, nil
+}
@@ -0,0 +1,49 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBuildPodDisruptionBudget(t *testing.T) {
+	vdb := testVdb()
+
+	pdb, err := buildPodDisruptionBudget(vdb)
+	assert.Nil(t, err)
+	assert.Equal(t, "dv", pdb.Name)
+	assert.Equal(t, matchLabels("dv"), pdb.Spec.Selector.MatchLabels)
+	assert.Nil(t, pdb.Spec.MinAvailable)
+	assert.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+
+	minAvailable := intstr.FromString("50%")
+	vdb.Spec.DisruptionBudget = &v1alpha1.DisruptionBudgetObject{MinAvailable: &minAvailable}
+	pdb, err = buildPodDisruptionBudget(vdb)
+	assert.Nil(t, err)
+	assert.Equal(t, "50%", pdb.Spec.MinAvailable.String())
+	assert.Nil(t, pdb.Spec.MaxUnavailable)
+
+	maxUnavailable := intstr.FromInt(2)
+	vdb.Spec.DisruptionBudget.MaxUnavailable = &maxUnavailable
+	_, err = buildPodDisruptionBudget(vdb)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,173 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// podTemplateDigestAnnotation annotation on the Deployment holding the digest of the generated pod template
+	podTemplateDigestAnnotation = "teiid.io/pod-template-digest"
+	// podTemplateSourceAnnotation annotation on the Deployment holding the digest of the vdb the pod template was
+	// generated from
+	podTemplateSourceAnnotation = "teiid.io/pod-template-source"
+)
+
+// applyPodTemplate overlays spec.podTemplate onto the generated pod template. Lists are merged on the name, the
+// labels, annotations, volumes, mounts and containers generated by the operator are kept when they collide
+func applyPodTemplate(vdb *v1alpha1.VirtualDatabase, template *corev1.PodTemplateSpec) {
+	overlay := vdb.Spec.PodTemplate
+	if overlay == nil {
+		return
+	}
+
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
+	}
+	for k, v := range overlay.Labels {
+		if _, ok := template.Labels[k]; !ok {
+			template.Labels[k] = v
+		}
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	for k, v := range overlay.Annotations {
+		if _, ok := template.Annotations[k]; !ok {
+			template.Annotations[k] = v
+		}
+	}
+
+	spec := &template.Spec
+	for _, volume := range overlay.Volumes {
+		if !hasVolume(spec.Volumes, volume.Name) {
+			spec.Volumes = append(spec.Volumes, volume)
+		} else {
+			log.Warn("Volume ", volume.Name, " of spec.podTemplate collides with the one generated, it is ignored")
+		}
+	}
+
+	main := &spec.Containers[0]
+	for _, mount := range overlay.VolumeMounts {
+		if !hasVolumeMount(main.VolumeMounts, mount) {
+			main.VolumeMounts = append(main.VolumeMounts, mount)
+		} else {
+			log.Warn("Volume mount ", mount.Name, " of spec.podTemplate collides with the one generated, it is ignored")
+		}
+	}
+	if overlay.ContainerSecurityContext != nil && main.SecurityContext == nil {
+		main.SecurityContext = overlay.ContainerSecurityContext
+	}
+
+	for _, container := range overlay.InitContainers {
+		if !hasContainer(spec.InitContainers, container.Name) {
+			spec.InitContainers = append(spec.InitContainers, container)
+		}
+	}
+	for _, container := range overlay.Sidecars {
+		if !hasContainer(spec.Containers, container.Name) {
+			spec.Containers = append(spec.Containers, container)
+		} else {
+			log.Warn("Sidecar ", container.Name, " of spec.podTemplate collides with the Virtual Database container, it is ignored")
+		}
+	}
+
+	if overlay.SecurityContext != nil && spec.SecurityContext == nil {
+		spec.SecurityContext = overlay.SecurityContext
+	}
+	if overlay.ServiceAccountName != "" && spec.ServiceAccountName == "" {
+		spec.ServiceAccountName = overlay.ServiceAccountName
+	}
+	for _, secret := range overlay.ImagePullSecrets {
+		found := false
+		for _, s := range spec.ImagePullSecrets {
+			if s.Name == secret.Name {
+				found = true
+			}
+		}
+		if !found {
+			spec.ImagePullSecrets = append(spec.ImagePullSecrets, secret)
+		}
+	}
+}
+
+func hasVolume(volumes []corev1.Volume, name string) bool {
+	for _, v := range volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVolumeMount(mounts []corev1.VolumeMount, mount corev1.VolumeMount) bool {
+	for _, m := range mounts {
+		if m.Name == mount.Name || m.MountPath == mount.MountPath {
+			return true
+		}
+	}
+	return false
+}
+
+func hasContainer(containers []corev1.Container, name string) bool {
+	for _, c := range containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// podTemplateDigest digest of the generated pod template, the server fills in defaults so the Deployment is
+// compared on the digest instead of the template. The configHash is left out, it is tracked on its own
+func podTemplateDigest(template corev1.PodTemplateSpec) (string, error) {
+	t := template.DeepCopy()
+	delete(t.Annotations, "configHash")
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+// podTemplateSource digest of the parts of the vdb the pod template is generated from: the spec other than the
+// replicas, the deployed versions and the build. It is computed without API calls, the pod template is generated
+// again only when it changes. The ConfigMaps and Secrets are tracked by the configHash
+func podTemplateSource(vdb *v1alpha1.VirtualDatabase) (string, error) {
+	spec := vdb.Spec.DeepCopy()
+	spec.Replicas = nil
+	data, err := json.Marshal(struct {
+		Spec          *v1alpha1.VirtualDatabaseSpec
+		Version       string
+		ActiveVersion string
+		Digest        string
+		CacheStore    string
+	}{spec, vdb.Status.Version, vdb.Status.ActiveVersion, vdb.Status.Digest, vdb.Status.CacheStore})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,134 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func generatedTemplate() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      matchLabels("dv"),
+			Annotations: map[string]string{"configHash": "c1"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:         "dv",
+					VolumeMounts: []corev1.VolumeMount{{Name: "keystore", MountPath: "/etc/tls/private"}},
+				},
+			},
+			Volumes: []corev1.Volume{{Name: "keystore"}},
+		},
+	}
+}
+
+func TestApplyPodTemplate(t *testing.T) {
+	nonRoot := true
+	vdb := testVdb()
+	vdb.Spec.PodTemplate = &v1alpha1.PodTemplateObject{
+		Labels:      map[string]string{"app": "other", "team": "finance"},
+		Annotations: map[string]string{"configHash": "x", "backup": "false"},
+		Volumes: []corev1.Volume{
+			{Name: "keystore"},
+			{Name: "drivers", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "drivers", MountPath: "/deployments/drivers"},
+			{Name: "certs", MountPath: "/etc/tls/private"},
+		},
+		InitContainers: []corev1.Container{{Name: "copy-drivers", Image: "drivers:latest"}},
+		Sidecars: []corev1.Container{
+			{Name: "dv", Image: "busybox"},
+			{Name: "cloud-sql-proxy", Image: "cloud-sql-proxy:latest"},
+		},
+		SecurityContext:    &corev1.PodSecurityContext{RunAsNonRoot: &nonRoot},
+		ServiceAccountName: "dv-sa",
+		ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}},
+	}
+
+	template := generatedTemplate()
+	applyPodTemplate(vdb, &template)
+
+	assert.Equal(t, "dv", template.Labels["app"])
+	assert.Equal(t, "finance", template.Labels["team"])
+	assert.Equal(t, "c1", template.Annotations["configHash"])
+	assert.Equal(t, "false", template.Annotations["backup"])
+
+	assert.Equal(t, 2, len(template.Spec.Volumes))
+	assert.Nil(t, template.Spec.Volumes[0].EmptyDir)
+	assert.Equal(t, "drivers", template.Spec.Volumes[1].Name)
+
+	mounts := template.Spec.Containers[0].VolumeMounts
+	assert.Equal(t, 2, len(mounts))
+	assert.Equal(t, "keystore", mounts[0].Name)
+	assert.Equal(t, "drivers", mounts[1].Name)
+
+	assert.Equal(t, 2, len(template.Spec.Containers))
+	assert.Equal(t, "", template.Spec.Containers[0].Image)
+	assert.Equal(t, "cloud-sql-proxy", template.Spec.Containers[1].Name)
+	assert.Equal(t, "copy-drivers", template.Spec.InitContainers[0].Name)
+	assert.True(t, *template.Spec.SecurityContext.RunAsNonRoot)
+	assert.Equal(t, "dv-sa", template.Spec.ServiceAccountName)
+	assert.Equal(t, "registry", template.Spec.ImagePullSecrets[0].Name)
+}
+
+func TestPodTemplateDigest(t *testing.T) {
+	template := generatedTemplate()
+	digest, err := podTemplateDigest(template)
+	assert.Nil(t, err)
+
+	// config changes are rolled out on their own
+	template.Annotations["configHash"] = "c2"
+	same, _ := podTemplateDigest(template)
+	assert.Equal(t, digest, same)
+	assert.Equal(t, "c2", template.Annotations["configHash"])
+
+	template.Spec.ServiceAccountName = "dv-sa"
+	changed, _ := podTemplateDigest(template)
+	assert.NotEqual(t, digest, changed)
+}
+
+func TestPodTemplateSource(t *testing.T) {
+	replicas := int32(1)
+	vdb := testVdb()
+	vdb.Spec.Replicas = &replicas
+	vdb.Status.Version = "1"
+	source, err := podTemplateSource(vdb)
+	assert.Nil(t, err)
+
+	// scaling does not generate the pod template again
+	replicas = 3
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+	same, _ := podTemplateSource(vdb)
+	assert.Equal(t, source, same)
+
+	vdb.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+	changed, _ := podTemplateSource(vdb)
+	assert.NotEqual(t, source, changed)
+
+	vdb.Status.Version = "2"
+	rebuilt, _ := podTemplateSource(vdb)
+	assert.NotEqual(t, changed, rebuilt)
+}
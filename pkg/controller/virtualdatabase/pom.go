@@ -24,7 +24,6 @@ import (
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
 	"github.com/teiid/teiid-operator/pkg/util/conf"
-	"github.com/teiid/teiid-operator/pkg/util/envvar"
 	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
 	"github.com/teiid/teiid-operator/pkg/util/maven"
 	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
@@ -32,6 +31,13 @@ import (
 )
 
 func addDependency(project *maven.Project, sourceType string, cf conf.ConnectionFactory) {
+	// source types registered through a ConnectionFactory resource bring their own artifacts
+	if len(cf.Dependencies) > 0 {
+		for _, gav := range cf.Dependencies {
+			project.AddEncodedDependencyGAV(gav)
+		}
+		return
+	}
 	dependency := maven.Dependency{
 		GroupID:    "org.teiid",
 		ArtifactID: "spring-data-" + cf.Name,
@@ -41,7 +47,7 @@ func addDependency(project *maven.Project, sourceType string, cf conf.Connection
 }
 
 // GenerateVdbPom -- Generate the POM file based on the VDb provided
-func GenerateVdbPom(vdb *v1alpha1.VirtualDatabase, sources []vdbutil.DatasourceInfo,
+func GenerateVdbPom(vdb *v1alpha1.VirtualDatabase, sources []vdbutil.DatasourceInfo, factories map[string]conf.ConnectionFactory,
 	includeAllDependencies bool, includeOpenAPIAdependency bool, includeIspnDependency bool) (maven.Project, error) {
 	// do code generation.
 	// generate pom.xml
@@ -56,13 +62,20 @@ func GenerateVdbPom(vdb *v1alpha1.VirtualDatabase, sources []vdbutil.DatasourceI
 		project.AddDependencies(d)
 	}
 
+	// drivers and translators of the data sources
+	deps, err := dataSourceDependencies(vdb.Spec.DataSources)
+	if err != nil {
+		return project, err
+	}
+	project.AddDependencies(deps...)
+
 	if includeAllDependencies {
-		for k, v := range constants.ConnectionFactories {
+		for k, v := range factories {
 			addDependency(&project, k, v)
 		}
 	} else {
 		for _, s := range sources {
-			if v, ok := constants.ConnectionFactories[s.Type]; ok {
+			if v, ok := factories[s.Type]; ok {
 				addDependency(&project, s.Type, v)
 			} else {
 				log.Info("No predefined Connection Factory found for ", s, " Treating as custom source, dependency must de defined in YAML file")
@@ -70,16 +83,6 @@ func GenerateVdbPom(vdb *v1alpha1.VirtualDatabase, sources []vdbutil.DatasourceI
 		}
 	}
 
-	for _, exposeType := range vdb.Spec.Expose {
-		if exposeType == v1alpha1.ExposeVia3scale {
-			project.AddDependencies(maven.Dependency{
-				GroupID:    "org.teiid",
-				ArtifactID: "spring-keycloak",
-				Version:    constants.Config.TeiidSpringBootVersion,
-			})
-		}
-	}
-
 	if includeIspnDependency {
 		project.AddDependencies(maven.Dependency{
 			GroupID:    "org.teiid",
@@ -89,8 +92,8 @@ func GenerateVdbPom(vdb *v1alpha1.VirtualDatabase, sources []vdbutil.DatasourceI
 	}
 
 	// add keyclock based security
-	if includeAllDependencies || envvar.Get(vdb.Spec.Env, "KEYCLOAK_AUTH_SERVER_URL") != nil {
-		log.Info("KEYCLOAK_AUTH_SERVER_URL found, enabling security module")
+	if includeAllDependencies || isSecurityEnabled(vdb) {
+		log.Info("Security configuration found, enabling security module")
 		project.AddDependencies(maven.Dependency{
 			GroupID:    "org.teiid",
 			ArtifactID: "spring-keycloak",
@@ -106,7 +109,8 @@ func GenerateVdbPom(vdb *v1alpha1.VirtualDatabase, sources []vdbutil.DatasourceI
 		})
 	}
 
-	if !includeOpenAPIAdependency || includeAllDependencies {
+	// OData turned off in spec.protocols is not served on any port
+	if includeAllDependencies || (!includeOpenAPIAdependency && isODataExposed(vdb)) {
 		project.AddDependencies(maven.Dependency{
 			GroupID:    "org.teiid",
 			ArtifactID: "spring-odata",
@@ -192,6 +196,50 @@ func addVdbCodeGenPlugIn(project *maven.Project, vdbFilePath string, materializa
 	project.PrependBuildPlugin(plugin)
 }
 
+// addSBOMPlugIns generates the CycloneDX bill of materials of the application and prints it to the build log,
+// between markers, from where the operator stores it
+func addSBOMPlugIns(project *maven.Project) {
+	project.AddBuildPlugin(maven.Plugin{
+		GroupID:    "org.cyclonedx",
+		ArtifactID: "cyclonedx-maven-plugin",
+		Version:    cycloneDXVersion,
+		Executions: []maven.Execution{
+			{
+				ID:    "sbom",
+				Phase: "package",
+				Goals: []string{
+					"makeBom",
+				},
+				Configuration: maven.Configuration{
+					OutputFormat: "json",
+					OutputName:   "bom",
+				},
+			},
+		},
+	})
+	project.AddBuildPlugin(maven.Plugin{
+		GroupID:    "org.apache.maven.plugins",
+		ArtifactID: "maven-antrun-plugin",
+		Version:    "3.0.0",
+		Executions: []maven.Execution{
+			{
+				ID:    "print-sbom",
+				Phase: "package",
+				Goals: []string{
+					"run",
+				},
+				Configuration: maven.Configuration{
+					Target: &maven.Target{
+						Tasks: `<echo message="` + sbomBeginMarker + `"/>` +
+							`<concat><filelist dir="${project.build.directory}" files="bom.json"/></concat>` +
+							`<echo message="` + sbomEndMarker + `"/>`,
+					},
+				},
+			},
+		},
+	})
+}
+
 func createMavenProject(name string) maven.Project {
 	project := maven.Project{
 		XMLName:           xml.Name{Local: "project"},
@@ -321,8 +369,21 @@ func createPlainMavenProject(name string) maven.Project {
 	return project
 }
 
+// configuredRepositories the maven repositories of the Virtual Database or the operator defaults
+func configuredRepositories(vdb *v1alpha1.VirtualDatabase) []maven.Repository {
+	repositories := []maven.Repository{}
+	for k, v := range constants.GetMavenRepositories(vdb) {
+		repositories = append(repositories, maven.NewRepository(v+"@id="+k))
+	}
+	return repositories
+}
+
 func readMavenSettingsFile(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase, configuredRepositories []maven.Repository) (string, error) {
-	settingsContent, err := maven.EncodeXML(maven.NewDefaultSettings(configuredRepositories))
+	settings := maven.NewDefaultSettings(configuredRepositories)
+	if mirror := mavenMirror(vdb); mirror != nil {
+		settings.Mirrors = &maven.Mirrors{Mirror: []maven.Mirror{*mirror}}
+	}
+	settingsContent, err := maven.EncodeXML(settings)
 
 	if kubernetes.HasSecret(ctx, r.client, vdb.ObjectMeta.Name+"-maven-settings", vdb.ObjectMeta.Namespace) {
 		selector := &corev1.SecretKeySelector{
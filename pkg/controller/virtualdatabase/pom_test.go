@@ -69,7 +69,7 @@ func TestPomGeneration(t *testing.T) {
 
 	dsInfo := vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL)
 
-	project, err := GenerateVdbPom(&vdb, dsInfo, false, false, false)
+	project, err := GenerateVdbPom(&vdb, dsInfo, constants.ConnectionFactories, false, false, false)
 	assert.Nil(t, err)
 	assert.True(t, hasDependency(project, "org.teiid", "spring-data-h2"))
 	assert.True(t, hasDependency(project, "org.teiid", "teiid-spring-boot-starter"))
@@ -87,7 +87,7 @@ func TestPomGeneration2(t *testing.T) {
 
 	dsInfo := vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL)
 
-	project, err := GenerateVdbPom(&vdb, dsInfo, false, false, false)
+	project, err := GenerateVdbPom(&vdb, dsInfo, constants.ConnectionFactories, false, false, false)
 	assert.Nil(t, err)
 	assert.True(t, hasDependency(project, "org.teiid", "spring-data-s3"))
 	assert.True(t, hasDependency(project, "org.teiid", "teiid-spring-boot-starter"))
@@ -123,7 +123,7 @@ func TestMinimalGavPomGeneration(t *testing.T) {
 		TranslatorName: "bar",
 	}
 
-	project, err := GenerateVdbPom(&vdb, dsInfo, false, false, false)
+	project, err := GenerateVdbPom(&vdb, dsInfo, constants.ConnectionFactories, false, false, false)
 	assert.Nil(t, err)
 	assert.True(t, hasDependency(project, "org.teiid", "spring-data-h2"))
 	assert.True(t, hasDependency(project, "org.teiid", "teiid-spring-boot-starter"))
@@ -132,3 +132,28 @@ func TestMinimalGavPomGeneration(t *testing.T) {
 	assert.True(t, hasDependency(project, "org.teiid", "spring-odata"))
 	assert.True(t, hasDependency(project, "me.snowdrop", "narayana-spring-boot-starter"))
 }
+
+func TestPomGenerationWithSecurity(t *testing.T) {
+	contents, _ := ioutil.ReadFile("../../../deploy/crds/vdb_from_ddl.yaml")
+	var vdb v1alpha1.VirtualDatabase
+	err := yaml.Unmarshal(contents, &vdb)
+	assert.Nil(t, err)
+
+	dsInfo := vdbutil.ParseDataSourcesInfoFromDdl(vdb.Spec.Build.Source.DDL)
+
+	vdb.Spec.Expose = []v1alpha1.ExposeType{v1alpha1.ExposeVia3scale}
+	project, err := GenerateVdbPom(&vdb, dsInfo, constants.ConnectionFactories, false, false, false)
+	assert.Nil(t, err)
+	assert.False(t, hasDependency(project, "org.teiid", "spring-keycloak"))
+
+	vdb.Spec.Security = &v1alpha1.SecurityObject{
+		OIDC: &v1alpha1.OIDCObject{
+			IssuerURL: "https://sso.example.com/auth",
+			Realm:     "teiid",
+			ClientID:  "portfolio",
+		},
+	}
+	project, err = GenerateVdbPom(&vdb, dsInfo, constants.ConnectionFactories, false, false, false)
+	assert.Nil(t, err)
+	assert.True(t, hasDependency(project, "org.teiid", "spring-keycloak"))
+}
@@ -0,0 +1,93 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	livenessPath  = "/actuator/health/liveness"
+	readinessPath = "/actuator/health/readiness"
+)
+
+// healthGroupEnvironments defines the liveness and readiness health groups of the actuator, liveness only checks
+// the application is up while readiness includes all the health indicators. Being set on the container rather than
+// in the application properties, the groups also exist in fat-jar images and images built by earlier versions
+func healthGroupEnvironments() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "MANAGEMENT_ENDPOINT_HEALTH_GROUP_LIVENESS_INCLUDE", Value: "ping"},
+		{Name: "MANAGEMENT_ENDPOINT_HEALTH_GROUP_READINESS_INCLUDE", Value: "*"},
+	}
+}
+
+// containerProbes returns the liveness, readiness and startup probes of the Virtual Database container
+func containerProbes(vdb *v1alpha1.VirtualDatabase) (*corev1.Probe, *corev1.Probe, *corev1.Probe) {
+	liveness := healthProbe(livenessPath, 15, 20, 5)
+	readiness := healthProbe(readinessPath, 15, 10, 3)
+	// importing foreign schemas of large databases can take minutes, give up after ten
+	startup := healthProbe(livenessPath, 10, 10, 60)
+
+	if vdb.Spec.Probes != nil {
+		liveness = overrideProbe(liveness, vdb.Spec.Probes.Liveness)
+		readiness = overrideProbe(readiness, vdb.Spec.Probes.Readiness)
+		startup = overrideProbe(startup, vdb.Spec.Probes.Startup)
+	}
+	return liveness, readiness, startup
+}
+
+func healthProbe(path string, initialDelay int32, period int32, failureThreshold int32) *corev1.Probe {
+	probe := &corev1.Probe{
+		TimeoutSeconds:      int32(5),
+		PeriodSeconds:       period,
+		SuccessThreshold:    int32(1),
+		FailureThreshold:    failureThreshold,
+		InitialDelaySeconds: initialDelay,
+	}
+	probe.Handler.HTTPGet = &corev1.HTTPGetAction{
+		Path: path,
+		Port: intstr.FromInt(8080),
+	}
+	return probe
+}
+
+// overrideProbe replaces the default probe, an override without a handler keeps the default health check
+func overrideProbe(defaultProbe *corev1.Probe, override *corev1.Probe) *corev1.Probe {
+	if override == nil {
+		return defaultProbe
+	}
+	probe := override.DeepCopy()
+	if probe.Handler.HTTPGet == nil && probe.Handler.TCPSocket == nil && probe.Handler.Exec == nil {
+		probe.Handler = defaultProbe.Handler
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = defaultProbe.TimeoutSeconds
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = defaultProbe.PeriodSeconds
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = defaultProbe.SuccessThreshold
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = defaultProbe.FailureThreshold
+	}
+	return probe
+}
@@ -0,0 +1,69 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/envvar"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDefaultProbes(t *testing.T) {
+	vdb := testVdb()
+	liveness, readiness, startup := containerProbes(vdb)
+
+	assert.Equal(t, livenessPath, liveness.HTTPGet.Path)
+	assert.Equal(t, readinessPath, readiness.HTTPGet.Path)
+	assert.Equal(t, livenessPath, startup.HTTPGet.Path)
+	assert.Equal(t, int32(8080), startup.HTTPGet.Port.IntVal)
+	assert.Equal(t, int32(600), startup.PeriodSeconds*startup.FailureThreshold)
+
+	envs := healthGroupEnvironments()
+	assert.Equal(t, "ping", envvar.Get(envs, "MANAGEMENT_ENDPOINT_HEALTH_GROUP_LIVENESS_INCLUDE").Value)
+	assert.Equal(t, "*", envvar.Get(envs, "MANAGEMENT_ENDPOINT_HEALTH_GROUP_READINESS_INCLUDE").Value)
+}
+
+func TestProbeOverrides(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.Probes = &v1alpha1.ProbesObject{
+		Startup: &corev1.Probe{FailureThreshold: 180},
+		Liveness: &corev1.Probe{
+			Handler: corev1.Handler{
+				Exec: &corev1.ExecAction{Command: []string{"/bin/true"}},
+			},
+			PeriodSeconds: 30,
+		},
+	}
+	liveness, readiness, startup := containerProbes(vdb)
+
+	// timings only, the health check is kept
+	assert.Equal(t, int32(180), startup.FailureThreshold)
+	assert.Equal(t, int32(10), startup.PeriodSeconds)
+	assert.Equal(t, livenessPath, startup.HTTPGet.Path)
+
+	assert.Nil(t, liveness.HTTPGet)
+	assert.Equal(t, "/bin/true", liveness.Exec.Command[0])
+	assert.Equal(t, int32(30), liveness.PeriodSeconds)
+	assert.Equal(t, int32(5), liveness.TimeoutSeconds)
+
+	assert.Equal(t, readinessPath, readiness.HTTPGet.Path)
+	assert.Nil(t, vdb.Spec.Probes.Startup.HTTPGet)
+}
@@ -0,0 +1,109 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+)
+
+func listenerEnabled(protocol *v1alpha1.ProtocolObject, secure bool, defaultValue bool) bool {
+	if protocol == nil {
+		return defaultValue
+	}
+	value := protocol.Insecure
+	if secure {
+		value = protocol.Secure
+	}
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func protocols(vdb *v1alpha1.VirtualDatabase) v1alpha1.ProtocolsObject {
+	if vdb.Spec.Protocols != nil {
+		return *vdb.Spec.Protocols
+	}
+	return v1alpha1.ProtocolsObject{}
+}
+
+// isJDBCEnabled tells whether the secure or insecure JDBC listener is enabled, both are by default
+func isJDBCEnabled(vdb *v1alpha1.VirtualDatabase, secure bool) bool {
+	return listenerEnabled(protocols(vdb).JDBC, secure, true)
+}
+
+// isPGEnabled tells whether the secure or insecure PostgreSQL listener is enabled, both are by default
+func isPGEnabled(vdb *v1alpha1.VirtualDatabase, secure bool) bool {
+	return listenerEnabled(protocols(vdb).PG, secure, true)
+}
+
+// isODataEnabled tells whether OData is served over https or http, http is the default unless https is enabled
+func isODataEnabled(vdb *v1alpha1.VirtualDatabase, secure bool) bool {
+	odata := protocols(vdb).OData
+	if secure {
+		return listenerEnabled(odata, true, false)
+	}
+	return listenerEnabled(odata, false, !listenerEnabled(odata, true, false))
+}
+
+// isODataExposed tells whether OData is served at all
+func isODataExposed(vdb *v1alpha1.VirtualDatabase) bool {
+	return isODataEnabled(vdb, true) || isODataEnabled(vdb, false)
+}
+
+// odataPort the port OData is served on
+func odataPort(vdb *v1alpha1.VirtualDatabase) int32 {
+	if isODataEnabled(vdb, true) {
+		return 8443
+	}
+	return 8080
+}
+
+func validateProtocols(vdb *v1alpha1.VirtualDatabase) error {
+	if isODataEnabled(vdb, true) && isODataEnabled(vdb, false) {
+		return errors.New("only one of secure or insecure can be enabled for spec.protocols.odata")
+	}
+	return nil
+}
+
+// protocolProperties returns application.properties entries that switch the listeners on and off
+func protocolProperties(vdb *v1alpha1.VirtualDatabase) []string {
+	props := []string{
+		"teiid.jdbc-secure-enable=" + strconv.FormatBool(isJDBCEnabled(vdb, true)),
+		"teiid.pg-secure-enable=" + strconv.FormatBool(isPGEnabled(vdb, true)),
+		"teiid.jdbc-enable=" + strconv.FormatBool(isJDBCEnabled(vdb, false)),
+		"teiid.pg-enable=" + strconv.FormatBool(isPGEnabled(vdb, false)),
+	}
+	if isODataEnabled(vdb, true) {
+		// OData moves to https, health checks and metrics stay on http port 8080
+		props = append(props,
+			"server.port=8443",
+			"server.ssl.enabled=true",
+			"server.ssl.key-store-type=pkcs12",
+			"server.ssl.key-store=file:"+constants.KeystoreLocation+"/"+constants.KeystoreName,
+			"server.ssl.key-store-password="+constants.KeystorePassword,
+			"management.server.port=8080",
+			"management.server.ssl.enabled=false",
+		)
+	}
+	return props
+}
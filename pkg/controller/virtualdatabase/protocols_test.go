@@ -0,0 +1,114 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+)
+
+func portNames(vdb *v1alpha1.VirtualDatabase, externalOnly bool) []string {
+	names := []string{}
+	for _, port := range containerPorts(vdb, externalOnly) {
+		names = append(names, port.Name)
+	}
+	return names
+}
+
+func TestDefaultProtocols(t *testing.T) {
+	vdb := testVdb()
+	assert.Equal(t, []string{"http", "jolokia", "prometheus", "teiid", "pg", "teiid-secure", "pg-secure"}, portNames(vdb, false))
+	assert.Equal(t, []string{"teiid-secure", "pg-secure"}, portNames(vdb, true))
+	assert.Nil(t, validateProtocols(vdb))
+	assert.Equal(t, int32(8080), odataPort(vdb))
+
+	props := applicationProperties(vdb, "foo")
+	assert.True(t, strings.Contains(props, "teiid.jdbc-enable=true"))
+	assert.True(t, strings.Contains(props, "teiid.pg-secure-enable=true"))
+	assert.False(t, strings.Contains(props, "server.ssl.enabled"))
+
+	service := buildService(vdb, true)
+	assert.Equal(t, 7, len(service.Spec.Ports))
+	assert.Equal(t, "true", service.Labels["discovery.3scale.net"])
+	assert.Equal(t, "8080", service.Annotations["discovery.3scale.net/port"])
+}
+
+func TestSecureOnlyProtocols(t *testing.T) {
+	disabled := false
+	enabled := true
+	vdb := testVdb()
+	vdb.Spec.Protocols = &v1alpha1.ProtocolsObject{
+		JDBC:  &v1alpha1.ProtocolObject{Insecure: &disabled},
+		PG:    &v1alpha1.ProtocolObject{Insecure: &disabled},
+		OData: &v1alpha1.ProtocolObject{Secure: &enabled},
+	}
+	assert.Nil(t, validateProtocols(vdb))
+	assert.Equal(t, []string{"http", "https", "jolokia", "prometheus", "teiid-secure", "pg-secure"}, portNames(vdb, false))
+	assert.Equal(t, int32(8443), odataPort(vdb))
+
+	props := applicationProperties(vdb, "foo")
+	assert.True(t, strings.Contains(props, "teiid.jdbc-enable=false"))
+	assert.True(t, strings.Contains(props, "teiid.pg-enable=false"))
+	assert.True(t, strings.Contains(props, "teiid.jdbc-secure-enable=true"))
+	assert.True(t, strings.Contains(props, "server.port=8443"))
+	assert.True(t, strings.Contains(props, "management.server.port=8080"))
+
+	service := buildService(vdb, true)
+	names := []string{}
+	for _, port := range service.Spec.Ports {
+		names = append(names, port.Name)
+	}
+	assert.Equal(t, []string{"https", "jolokia", "prometheus", "teiid-secure", "pg-secure"}, names)
+	assert.Equal(t, "https", service.Annotations["discovery.3scale.net/scheme"])
+	assert.Equal(t, "8443", service.Annotations["discovery.3scale.net/port"])
+
+	// both listeners of odata can not be enabled
+	vdb.Spec.Protocols.OData.Insecure = &enabled
+	assert.NotNil(t, validateProtocols(vdb))
+}
+
+func TestODataDisabled(t *testing.T) {
+	disabled := false
+	vdb := testVdb()
+	vdb.Spec.Protocols = &v1alpha1.ProtocolsObject{
+		OData: &v1alpha1.ProtocolObject{Insecure: &disabled},
+	}
+	assert.False(t, isODataExposed(vdb))
+	// http stays for the health checks
+	assert.Equal(t, "http", containerPorts(vdb, false)[0].Name)
+
+	service := buildService(vdb, true)
+	assert.Equal(t, "jolokia", service.Spec.Ports[0].Name)
+	assert.Equal(t, "false", service.Labels["discovery.3scale.net"])
+	assert.Equal(t, "", service.Annotations["discovery.3scale.net/port"])
+
+	// the OData endpoint is not part of the application either
+	vdb.Spec.Build.Source.DDL = "CREATE DATABASE dv; USE DATABASE dv; CREATE VIRTUAL SCHEMA portfolio;"
+	project, err := GenerateVdbPom(vdb, nil, constants.ConnectionFactories, false, false, false)
+	assert.Nil(t, err)
+	assert.False(t, hasDependency(project, "org.teiid", "spring-odata"))
+
+	vdb.Spec.Protocols = nil
+	project, err = GenerateVdbPom(vdb, nil, constants.ConnectionFactories, false, false, false)
+	assert.Nil(t, err)
+	assert.True(t, hasDependency(project, "org.teiid", "spring-odata"))
+}
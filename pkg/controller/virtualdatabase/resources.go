@@ -0,0 +1,79 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// resourcePolicyConfigMap ConfigMap in the namespace of the vdb overriding the resource policy of the operator
+	resourcePolicyConfigMap = "teiid-resource-policy"
+	// resourcePolicyKey key of the ConfigMap holding the policy, same format as the resources of config.yaml
+	resourcePolicyKey = "policy.yaml"
+	// jvmOverhead memory left to the metaspace, threads and buffers outside of the heap
+	jvmOverhead = 256 * 1024 * 1024
+)
+
+// computingResources returns the requests and limits of the Virtual Database container after applying the
+// resource policy of the operator and the one of the namespace, which can only tighten the bounds of the operator
+func computingResources(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (corev1.ResourceRequirements, error) {
+	policy := constants.Config.Resources
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: resourcePolicyConfigMap, Namespace: vdb.ObjectMeta.Namespace}, cm)
+	if err == nil {
+		namespacePolicy, err := conf.ParseResourcePolicy([]byte(cm.Data[resourcePolicyKey]))
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("invalid resource policy in ConfigMap %s: %v", resourcePolicyConfigMap, err)
+		}
+		policy, err = policy.Merge(namespacePolicy)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("invalid resource policy in ConfigMap %s: %v", resourcePolicyConfigMap, err)
+		}
+	} else if !k8serrors.IsNotFound(err) {
+		return corev1.ResourceRequirements{}, err
+	}
+	return constants.GetComputingResources(vdb, policy)
+}
+
+// jvmEnvironments sizes the heap from the memory limit, a small container needs a larger share for the memory
+// outside of the heap. The ratio is left to the image when there is no memory limit
+func jvmEnvironments(resources corev1.ResourceRequirements) []corev1.EnvVar {
+	limit, ok := resources.Limits[corev1.ResourceMemory]
+	if !ok || limit.Value() <= 0 {
+		return nil
+	}
+	ratio := (limit.Value() - jvmOverhead) * 100 / limit.Value()
+	if ratio < 25 {
+		ratio = 25
+	} else if ratio > 80 {
+		ratio = 80
+	}
+	return []corev1.EnvVar{
+		{Name: "JAVA_MAX_MEM_RATIO", Value: strconv.FormatInt(ratio, 10)},
+	}
+}
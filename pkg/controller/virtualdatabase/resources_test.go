@@ -0,0 +1,91 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDefaultComputingResources(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{}
+	resources, err := constants.GetComputingResources(&vdb, constants.Config.Resources)
+	assert.Nil(t, err)
+	assert.Equal(t, "512Mi", resources.Limits.Memory().String())
+	assert.Equal(t, "1", resources.Limits.Cpu().String())
+	assert.Equal(t, "256Mi", resources.Requests.Memory().String())
+	assert.Equal(t, "200m", resources.Requests.Cpu().String())
+	assert.Nil(t, vdb.Spec.Resources.Limits)
+}
+
+func TestComputingResourcesWithinDefaults(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{}
+	vdb.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+	vdb.Spec.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+
+	resources, err := constants.GetComputingResources(&vdb, constants.Config.Resources)
+	assert.Nil(t, err)
+	// the default limit is raised to the request, the default request lowered to the limit
+	assert.Equal(t, "1Gi", resources.Limits.Memory().String())
+	assert.Equal(t, "100m", resources.Requests.Cpu().String())
+}
+
+func TestResourcePolicyBounds(t *testing.T) {
+	policy, err := constants.Config.Resources.Merge(conf.ResourcePolicy{
+		Min: map[string]string{"memory": "256Mi"},
+		Max: map[string]string{"memory": "2Gi", "cpu": "2"},
+	})
+	assert.Nil(t, err)
+
+	vdb := v1alpha1.VirtualDatabase{}
+	vdb.Spec.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}
+	_, err = constants.GetComputingResources(&vdb, policy)
+	assert.NotNil(t, err)
+
+	vdb.Spec.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")}
+	_, err = constants.GetComputingResources(&vdb, policy)
+	assert.NotNil(t, err)
+
+	vdb.Spec.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}
+	_, err = constants.GetComputingResources(&vdb, policy)
+	assert.Nil(t, err)
+
+	policy.DefaultLimits["cpu"] = "lots"
+	_, err = constants.GetComputingResources(&vdb, policy)
+	assert.NotNil(t, err)
+}
+
+func TestJvmEnvironments(t *testing.T) {
+	ratio := func(memory string) string {
+		envs := jvmEnvironments(corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		})
+		return envs[0].Value
+	}
+	assert.Equal(t, "25", ratio("256Mi"))
+	assert.Equal(t, "50", ratio("512Mi"))
+	assert.Equal(t, "75", ratio("1Gi"))
+	assert.Equal(t, "80", ratio("4Gi"))
+	assert.Equal(t, 0, len(jvmEnvironments(corev1.ResourceRequirements{})))
+}
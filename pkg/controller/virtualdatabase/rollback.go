@@ -0,0 +1,210 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	obuildv1 "github.com/openshift/api/build/v1"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// rollbackAnnotation alternative to spec.rollbackTo, removed once the rollback started
+	rollbackAnnotation = "teiid.io/rollback-to"
+	maxHistory         = 10
+	maxTagLength       = 128
+)
+
+var invalidTagChars = regexp.MustCompile("[^A-Za-z0-9_.-]+")
+
+// imageTag is the tag of the ImageStream the image of the version is pushed to
+func imageTag(version string) string {
+	tag := strings.TrimLeft(invalidTagChars.ReplaceAllString(version, "-"), "-.")
+	if len(tag) > maxTagLength {
+		tag = tag[:maxTagLength]
+	}
+	if tag == "" {
+		return "latest"
+	}
+	return tag
+}
+
+// serviceImageOutput is the ImageStreamTag the build of the current version outputs to
+func serviceImageOutput(vdb *v1alpha1.VirtualDatabase) *corev1.ObjectReference {
+	return &corev1.ObjectReference{Name: vdb.ObjectMeta.Name + ":" + imageTag(vdb.Status.Version), Kind: "ImageStreamTag"}
+}
+
+// deploymentImage is the image the pods of the current version run, pinned to the digest the build of the version
+// pushed. Falls back to the tag for versions built before the history was kept
+func deploymentImage(vdb *v1alpha1.VirtualDatabase, bc obuildv1.BuildConfig) string {
+	if entry := findBuild(vdb, vdb.Status.Version); entry != nil && entry.Image != "" {
+		return entry.Image
+	}
+	return bc.Spec.Output.To.Name
+}
+
+// pinnedImage is the reference of the image by its digest, such as registry/namespace/name@sha256:...
+func pinnedImage(reference string, digest string) string {
+	if digest == "" || strings.Contains(reference, "@") {
+		return reference
+	}
+	name := reference
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name + "@" + digest
+}
+
+// imagePullPolicy pulls the images referenced by a tag on every start, as the tag may have been moved by a new
+// build, while the pinned images never change
+func imagePullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	return corev1.PullAlways
+}
+
+// setImage sets the image of the container, returns true when it changed
+func setImage(container *corev1.Container, image string) bool {
+	if container.Image == image {
+		return false
+	}
+	container.Image = image
+	container.ImagePullPolicy = imagePullPolicy(image)
+	return true
+}
+
+func findBuild(vdb *v1alpha1.VirtualDatabase, version string) *v1alpha1.BuildHistoryEntry {
+	for i := range vdb.Status.History {
+		if vdb.Status.History[i].Version == version {
+			return &vdb.Status.History[i]
+		}
+	}
+	return nil
+}
+
+// recordBuild adds the image of the current version to the history, replacing an earlier build of the same
+// version. Returns the entries that no longer fit into the history
+func recordBuild(vdb *v1alpha1.VirtualDatabase, image string, now time.Time) []v1alpha1.BuildHistoryEntry {
+	history := []v1alpha1.BuildHistoryEntry{}
+	for _, entry := range vdb.Status.History {
+		if entry.Version != vdb.Status.Version {
+			history = append(history, entry)
+		}
+	}
+	history = append(history, v1alpha1.BuildHistoryEntry{
+		Version: vdb.Status.Version,
+		Digest:  vdb.Status.Digest,
+		Image:   image,
+		Built:   metav1.NewTime(now),
+	})
+
+	var dropped []v1alpha1.BuildHistoryEntry
+	if len(history) > maxHistory {
+		dropped = history[:len(history)-maxHistory]
+		history = history[len(history)-maxHistory:]
+	}
+	vdb.Status.History = history
+	return dropped
+}
+
+// recordServiceImage stores the image the completed build pushed in the history, resolved to its digest, and
+// removes the tags and the bills of materials of the versions dropped from it
+func recordServiceImage(ctx context.Context, vdb *v1alpha1.VirtualDatabase, build obuildv1.Build, r *ReconcileVirtualDatabase) error {
+	if build.Spec.Output.To == nil {
+		return nil
+	}
+	tag, err := r.imageClient.ImageStreamTags(vdb.ObjectMeta.Namespace).Get(build.Spec.Output.To.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	image := pinnedImage(tag.Image.DockerImageReference, tag.Image.Name)
+	for _, entry := range recordBuild(vdb, image, time.Now()) {
+		name := vdb.ObjectMeta.Name + ":" + imageTag(entry.Version)
+		if name == build.Spec.Output.To.Name {
+			continue
+		}
+		err = r.imageClient.ImageStreamTags(vdb.ObjectMeta.Namespace).Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Removed image ", name, " of version no longer in the history")
+		if err = removeSBOM(ctx, vdb, entry.Version, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackVersion is the version requested through spec.rollbackTo or the rollback annotation
+func rollbackVersion(vdb *v1alpha1.VirtualDatabase) string {
+	if vdb.Spec.RollbackTo != "" {
+		return vdb.Spec.RollbackTo
+	}
+	return vdb.ObjectMeta.Annotations[rollbackAnnotation]
+}
+
+// IsRollbackRequested --
+func IsRollbackRequested(vdb *v1alpha1.VirtualDatabase) bool {
+	return rollbackVersion(vdb) != ""
+}
+
+// RollbackVdb redeploys the stored image of the requested version without rebuilding it, and clears the request
+func RollbackVdb(vdb *v1alpha1.VirtualDatabase) {
+	version := rollbackVersion(vdb)
+	vdb.Spec.RollbackTo = ""
+	delete(vdb.ObjectMeta.Annotations, rollbackAnnotation)
+
+	entry := findBuild(vdb, version)
+	if entry == nil {
+		vdb.Status.Failure = fmt.Sprintf("rollback to version %s failed, the version is not in the build history", version)
+		return
+	}
+	vdb.Status.Version = entry.Version
+	vdb.Status.Canary = nil
+	vdb.Status.Failure = ""
+	vdb.Status.VerificationFailed = nil
+	// services and certificates are in place already, skip the build and deploy the stored image
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseServiceImageFinished
+	log.Infof("Rolling back VDB %s to version %s", vdb.ObjectMeta.Name, entry.Version)
+}
+
+// latestVersion is the highest numeric version built so far, the version a rebuild increments after a rollback
+func latestVersion(vdb *v1alpha1.VirtualDatabase) string {
+	latest := vdb.Status.Version
+	max, err := strconv.Atoi(latest)
+	if err != nil {
+		return latest
+	}
+	for _, entry := range vdb.Status.History {
+		if ver, err := strconv.Atoi(entry.Version); err == nil && ver > max {
+			max = ver
+			latest = entry.Version
+		}
+	}
+	return latest
+}
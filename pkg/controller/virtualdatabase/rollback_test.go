@@ -0,0 +1,149 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	obuildv1 "github.com/openshift/api/build/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func rolledOutVdb() *v1alpha1.VirtualDatabase {
+	vdb := testVdb()
+	vdb.Spec.Build.Source.DDL = "CREATE DATABASE dv;"
+	vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+	vdb.Status.Version = "3"
+	vdb.Status.Digest = "v3"
+	vdb.Status.History = []v1alpha1.BuildHistoryEntry{
+		{Version: "2", Digest: "v2", Image: "registry/myproject/dv@sha256:2"},
+		{Version: "3", Digest: "v3", Image: "registry/myproject/dv@sha256:3"},
+	}
+	return vdb
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "3", imageTag("3"))
+	assert.Equal(t, "1.0-SNAPSHOT", imageTag("1.0-SNAPSHOT"))
+	assert.Equal(t, "1.0-beta-2", imageTag("1.0+beta 2"))
+	assert.Equal(t, "latest", imageTag(""))
+
+	vdb := rolledOutVdb()
+	assert.Equal(t, "dv:3", serviceImageOutput(vdb).Name)
+	assert.Equal(t, "ImageStreamTag", serviceImageOutput(vdb).Kind)
+}
+
+func TestRecordBuild(t *testing.T) {
+	vdb := rolledOutVdb()
+	now := time.Now()
+
+	// a rebuild of the version replaces its entry
+	vdb.Status.Digest = "v3b"
+	dropped := recordBuild(vdb, "registry/myproject/dv@sha256:3b", now)
+	assert.Empty(t, dropped)
+	assert.Len(t, vdb.Status.History, 2)
+	assert.Equal(t, "v3b", findBuild(vdb, "3").Digest)
+	assert.Equal(t, "registry/myproject/dv@sha256:3b", findBuild(vdb, "3").Image)
+	assert.Equal(t, metav1.NewTime(now), findBuild(vdb, "3").Built)
+
+	// the history is bounded, the oldest builds are dropped
+	for i := 4; i < 4+maxHistory; i++ {
+		vdb.Status.Version = strconv.Itoa(i)
+		dropped = recordBuild(vdb, "registry/myproject/dv@sha256:"+vdb.Status.Version, now)
+	}
+	assert.Len(t, vdb.Status.History, maxHistory)
+	assert.Len(t, dropped, 1)
+	assert.Equal(t, "3", dropped[0].Version)
+	assert.Nil(t, findBuild(vdb, "2"))
+	assert.Equal(t, "13", vdb.Status.History[maxHistory-1].Version)
+}
+
+func TestDeploymentImage(t *testing.T) {
+	vdb := rolledOutVdb()
+	bc := obuildv1.BuildConfig{}
+	bc.Spec.Output.To = &corev1.ObjectReference{Name: "dv:3", Kind: "ImageStreamTag"}
+
+	// pinned to the digest of the build of the version
+	assert.Equal(t, "registry/myproject/dv@sha256:3", deploymentImage(vdb, bc))
+
+	// rolled back, the stored image
+	vdb.Status.Version = "2"
+	assert.Equal(t, "registry/myproject/dv@sha256:2", deploymentImage(vdb, bc))
+
+	// built before the history was kept, the tag of the build
+	vdb.Status.History = nil
+	assert.Equal(t, "dv:3", deploymentImage(vdb, bc))
+}
+
+func TestPinnedImage(t *testing.T) {
+	digest := "sha256:4f3c"
+	assert.Equal(t, "registry:5000/myproject/dv@sha256:4f3c", pinnedImage("registry:5000/myproject/dv:3", digest))
+	assert.Equal(t, "registry:5000/myproject/dv@sha256:4f3c", pinnedImage("registry:5000/myproject/dv", digest))
+	assert.Equal(t, "registry/myproject/dv@sha256:1", pinnedImage("registry/myproject/dv@sha256:1", digest))
+	assert.Equal(t, "registry/myproject/dv:3", pinnedImage("registry/myproject/dv:3", ""))
+
+	assert.Equal(t, corev1.PullIfNotPresent, imagePullPolicy("registry/myproject/dv@sha256:1"))
+	assert.Equal(t, corev1.PullAlways, imagePullPolicy("dv:3"))
+
+	container := corev1.Container{Image: "dv:3", ImagePullPolicy: corev1.PullAlways}
+	assert.False(t, setImage(&container, "dv:3"))
+	assert.True(t, setImage(&container, "registry/myproject/dv@sha256:1"))
+	assert.Equal(t, "registry/myproject/dv@sha256:1", container.Image)
+	assert.Equal(t, corev1.PullIfNotPresent, container.ImagePullPolicy)
+}
+
+func TestRollbackVdb(t *testing.T) {
+	vdb := rolledOutVdb()
+	assert.False(t, IsRollbackRequested(vdb))
+
+	vdb.Spec.RollbackTo = "2"
+	vdb.Status.Canary = &v1alpha1.CanaryStatus{Version: "3"}
+	assert.True(t, IsRollbackRequested(vdb))
+	RollbackVdb(vdb)
+	assert.False(t, IsRollbackRequested(vdb))
+	assert.Equal(t, "2", vdb.Status.Version)
+	assert.Equal(t, "v3", vdb.Status.Digest)
+	assert.Nil(t, vdb.Status.Canary)
+	assert.Equal(t, v1alpha1.ReconcilerPhaseServiceImageFinished, vdb.Status.Phase)
+
+	// the rollback does not cause a rebuild, the next change builds the version after the latest
+	assert.Equal(t, "3", latestVersion(vdb))
+	vdb.Status.Digest, _ = ComputeForVirtualDatabase(vdb, constants.ConnectionFactories)
+	assert.False(t, IsVdbUpdated(vdb, constants.ConnectionFactories))
+	vdb.Spec.Build.Source.DDL = "CREATE DATABASE dv2;"
+	assert.NoError(t, RedeployVdb(vdb, constants.ConnectionFactories))
+	assert.Equal(t, "4", vdb.Status.Version)
+}
+
+func TestRollbackVdbAnnotation(t *testing.T) {
+	vdb := rolledOutVdb()
+	vdb.ObjectMeta.Annotations = map[string]string{rollbackAnnotation: "1"}
+	assert.True(t, IsRollbackRequested(vdb))
+
+	RollbackVdb(vdb)
+	assert.False(t, IsRollbackRequested(vdb))
+	assert.Equal(t, "3", vdb.Status.Version)
+	assert.Equal(t, v1alpha1.ReconcilerPhaseRunning, vdb.Status.Phase)
+	assert.Contains(t, vdb.Status.Failure, "version 1")
+}
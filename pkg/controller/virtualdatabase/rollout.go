@@ -0,0 +1,369 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	obuildv1 "github.com/openshift/api/build/v1"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// deploymentVersionLabel label carrying the vdb version on the Service and on the pods of the BlueGreen strategy
+	deploymentVersionLabel = "teiid.io/deployment-version"
+	defaultRetentionPeriod = time.Hour
+	// verificationTimeout how long a failing verification of the deploying version is retried before it fails
+	verificationTimeout = 5 * time.Minute
+)
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9.-]+")
+
+// isVersioned tells whether every version is deployed as its own Deployment, as done by the BlueGreen and Canary
+// strategies
+func isVersioned(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Spec.Rollout != nil && (vdb.Spec.Rollout.Strategy == v1alpha1.RolloutBlueGreen || isCanary(vdb))
+}
+
+func isCanary(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Spec.Rollout != nil && vdb.Spec.Rollout.Strategy == v1alpha1.RolloutCanary
+}
+
+func validateRollout(vdb *v1alpha1.VirtualDatabase) error {
+	if !isVersioned(vdb) {
+		return nil
+	}
+	if usesStatefulSet(vdb) {
+		return errors.New("the BlueGreen and Canary rollout strategies can not be combined with the PerReplica buffer storage")
+	}
+	if _, err := retentionPeriod(vdb); err != nil {
+		return err
+	}
+	return validateCanary(vdb)
+}
+
+func retentionPeriod(vdb *v1alpha1.VirtualDatabase) (time.Duration, error) {
+	if vdb.Spec.Rollout.BlueGreen == nil || vdb.Spec.Rollout.BlueGreen.RetentionPeriod == "" {
+		return defaultRetentionPeriod, nil
+	}
+	period, err := time.ParseDuration(vdb.Spec.Rollout.BlueGreen.RetentionPeriod)
+	if err != nil {
+		return 0, fmt.Errorf("invalid spec.rollout.blueGreen.retentionPeriod %q: %v", vdb.Spec.Rollout.BlueGreen.RetentionPeriod, err)
+	}
+	return period, nil
+}
+
+// versionDeploymentName name of the Deployment of a version, <vdb>-v<version>. An empty version is the Deployment
+// of the Rolling strategy
+func versionDeploymentName(vdb *v1alpha1.VirtualDatabase, version string) string {
+	if version == "" {
+		return vdb.ObjectMeta.Name
+	}
+	suffix := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(version), "-"), "-.")
+	return vdb.ObjectMeta.Name + "-v" + suffix
+}
+
+// servingVersion version the clients are routed to
+func servingVersion(vdb *v1alpha1.VirtualDatabase) string {
+	if isVersioned(vdb) && vdb.Status.ActiveVersion != "" {
+		return vdb.Status.ActiveVersion
+	}
+	return vdb.Status.Version
+}
+
+// serviceSelector selects the pods of the active version with the BlueGreen and Canary strategies, otherwise all
+// the pods of the vdb
+func serviceSelector(vdb *v1alpha1.VirtualDatabase) map[string]string {
+	selector := matchLabels(vdb.ObjectMeta.Name)
+	if isVersioned(vdb) && vdb.Status.ActiveVersion != "" {
+		selector[deploymentVersionLabel] = vdb.Status.ActiveVersion
+	}
+	return selector
+}
+
+// applyVersion turns the generated Deployment into the Deployment of the current version
+func applyVersion(vdb *v1alpha1.VirtualDatabase, dc *appsv1.Deployment) {
+	version := vdb.Status.Version
+	dc.ObjectMeta.Name = versionDeploymentName(vdb, version)
+	dc.ObjectMeta.Labels = copyWith(dc.ObjectMeta.Labels, deploymentVersionLabel, version)
+	dc.Spec.Template.ObjectMeta.Labels = copyWith(dc.Spec.Template.ObjectMeta.Labels, deploymentVersionLabel, version)
+	dc.Spec.Selector.MatchLabels = copyWith(dc.Spec.Selector.MatchLabels, deploymentVersionLabel, version)
+}
+
+func copyWith(m map[string]string, key string, value string) map[string]string {
+	c := make(map[string]string)
+	for k, v := range m {
+		c[k] = v
+	}
+	c[key] = value
+	return c
+}
+
+// switchVersion routes the clients to the version, the replaced version is kept for the retention period
+func switchVersion(vdb *v1alpha1.VirtualDatabase, version string, now time.Time) error {
+	if vdb.Status.ActiveVersion == version {
+		// a rebuild of the same version updated its Deployment in place
+		return nil
+	}
+	period, err := retentionPeriod(vdb)
+	if err != nil {
+		return err
+	}
+	expiry := metav1.NewTime(now.Add(period))
+	vdb.Status.PreviousVersion = vdb.Status.ActiveVersion
+	vdb.Status.PreviousVersionExpiry = &expiry
+	vdb.Status.ActiveVersion = version
+	log.Info("Switched ", vdb.ObjectMeta.Name, " to version ", version)
+	return nil
+}
+
+// retainedDeployments names of the Deployments that are kept, the active one and the previous one until it expires
+func retainedDeployments(vdb *v1alpha1.VirtualDatabase, now time.Time) []string {
+	names := []string{versionDeploymentName(vdb, vdb.Status.ActiveVersion)}
+	if vdb.Status.PreviousVersionExpiry != nil && now.Before(vdb.Status.PreviousVersionExpiry.Time) {
+		names = append(names, versionDeploymentName(vdb, vdb.Status.PreviousVersion))
+	}
+	return names
+}
+
+// ensureVersionDeployment creates the Deployment of the current version, or updates the image of the existing one.
+// A StatefulSet the vdb ran with before is removed by handleVersions once the version is switched
+func (action *deploymentAction) ensureVersionDeployment(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	bc obuildv1.BuildConfig, r *ReconcileVirtualDatabase) error {
+
+	existing, err := findDeployment(vdb, versionDeploymentName(vdb, vdb.Status.Version), r)
+	if k8serrors.IsNotFound(err) {
+		dc, err := action.buildDeployment(vdb, bc, r)
+		if err != nil {
+			return err
+		}
+		if err = r.client.Create(ctx, &dc); err != nil {
+			return err
+		}
+		log.Info("Deployment created:", dc.Name)
+	} else if err != nil {
+		return err
+	} else if setImage(&existing.Spec.Template.Spec.Containers[0], deploymentImage(vdb, bc)) {
+		if err = r.client.Update(ctx, existing); err != nil {
+			log.Warn("Failed to update object. ", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// handleVersions waits for the Deployment of the new version and verifies it. BlueGreen switches the clients over
+// at once, Canary shifts them in steps. Once running the Deployments that are no longer retained are removed
+func (action *deploymentAction) handleVersions(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) error {
+
+	if vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying {
+		item, err := findDeployment(vdb, versionDeploymentName(vdb, vdb.Status.Version), r)
+		if k8serrors.IsNotFound(err) {
+			vdb.Status.Phase = v1alpha1.ReconcilerPhaseKeystoreCreated
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if vdb.Status.Canary != nil && vdb.Status.Canary.Version == vdb.Status.Version {
+			return action.progressCanary(ctx, vdb, item, r, time.Now())
+		}
+		if !action.isDeploymentInReadyState(*item) {
+			if !action.isDeploymentProgressing(*item) {
+				log.Info("Deployment Failed:" + item.Name)
+				vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
+			}
+			return nil
+		}
+		if err := verifyDeployment(ctx, vdb, item, r); err != nil {
+			if retryVerification(vdb, time.Now()) {
+				log.Infof("verification of version %s failed, retrying: %v", vdb.Status.Version, err)
+				return nil
+			}
+			vdb.Status.Failure = fmt.Sprintf("verification of version %s failed: %v", vdb.Status.Version, err)
+			vdb.Status.Phase = v1alpha1.ReconcilerPhaseError
+			return nil
+		}
+		vdb.Status.VerificationFailed = nil
+		if isCanary(vdb) && vdb.Status.ActiveVersion != "" && vdb.Status.ActiveVersion != vdb.Status.Version {
+			startCanary(vdb, time.Now())
+			return nil
+		}
+		if err := switchVersion(vdb, vdb.Status.Version, time.Now()); err != nil {
+			return err
+		}
+		// the StatefulSet serves the clients until the version is verified and switched
+		if err := removeStatefulSet(ctx, vdb, r); err != nil {
+			return err
+		}
+		updateReplicaStatus(vdb, *item)
+		log.Info("Deployment finished:" + item.Name)
+		vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+		return nil
+	}
+
+	item, err := findDeployment(vdb, versionDeploymentName(vdb, vdb.Status.ActiveVersion), r)
+	if k8serrors.IsNotFound(err) || vdb.Status.ActiveVersion == "" {
+		// switched from the Rolling strategy or removed, deploy the current version
+		vdb.Status.Phase = v1alpha1.ReconcilerPhaseKeystoreCreated
+		return nil
+	} else if err != nil {
+		return err
+	}
+	updateReplicaStatus(vdb, *item)
+	if action.isDeploymentInReadyState(*item) && vdb.Status.ActiveVersion == vdb.Status.Version {
+		if err := action.ensureReplicas(ctx, vdb, item, r); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if vdb.Status.PreviousVersionExpiry != nil && !now.Before(vdb.Status.PreviousVersionExpiry.Time) {
+		vdb.Status.PreviousVersion = ""
+		vdb.Status.PreviousVersionExpiry = nil
+	}
+	return removeStaleDeployments(ctx, vdb, retainedDeployments(vdb, now), r)
+}
+
+// removeStaleDeployments removes the Deployments of the vdb other than the retained ones
+func removeStaleDeployments(ctx context.Context, vdb *v1alpha1.VirtualDatabase, retained []string,
+	r *ReconcileVirtualDatabase) error {
+
+	list := &appsv1.DeploymentList{}
+	err := r.client.List(ctx, list, client.InNamespace(vdb.ObjectMeta.Namespace),
+		client.MatchingLabels{"teiid.io/VirtualDatabase": vdb.ObjectMeta.Name})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		dc := &list.Items[i]
+		if isRetained(dc.Name, retained) || !metav1.IsControlledBy(dc, vdb) {
+			continue
+		}
+		if err := r.client.Delete(ctx, dc); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Deployment removed:", dc.Name)
+	}
+	return nil
+}
+
+func isRetained(name string, retained []string) bool {
+	for _, n := range retained {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// retryVerification records the time the verification first failed and tells whether it is retried, which it is
+// until verificationTimeout elapsed. The pods of a new version may answer before the sources are reachable
+func retryVerification(vdb *v1alpha1.VirtualDatabase, now time.Time) bool {
+	if vdb.Status.VerificationFailed == nil {
+		failed := metav1.NewTime(now)
+		vdb.Status.VerificationFailed = &failed
+	}
+	if now.Before(vdb.Status.VerificationFailed.Add(verificationTimeout)) {
+		return true
+	}
+	vdb.Status.VerificationFailed = nil
+	return false
+}
+
+// verifyDeployment runs the verification query against a ready pod of the Deployment. The pod is queried
+// directly by the operator, buildNetworkPolicy lets the operator in when a verification path is set
+func verifyDeployment(ctx context.Context, vdb *v1alpha1.VirtualDatabase, dc *appsv1.Deployment,
+	r *ReconcileVirtualDatabase) error {
+
+	if vdb.Spec.Rollout.BlueGreen == nil || vdb.Spec.Rollout.BlueGreen.VerificationPath == "" {
+		return nil
+	}
+	if !isODataEnabled(vdb, true) && !isODataEnabled(vdb, false) {
+		return errors.New("the verification query needs OData to be enabled")
+	}
+
+	pods := &corev1.PodList{}
+	err := r.client.List(ctx, pods, client.InNamespace(dc.Namespace), client.MatchingLabels(dc.Spec.Selector.MatchLabels))
+	if err != nil {
+		return err
+	}
+	podIP := ""
+	for _, pod := range pods.Items {
+		if isPodReady(pod) && pod.Status.PodIP != "" {
+			podIP = pod.Status.PodIP
+			break
+		}
+	}
+	if podIP == "" {
+		return errors.New("no ready pod")
+	}
+
+	scheme := "http"
+	if isODataEnabled(vdb, true) {
+		scheme = "https"
+	}
+	url := verificationURL(scheme, podIP, odataPort(vdb), vdb.Spec.Rollout.BlueGreen.VerificationPath)
+	httpClient := &http.Client{
+		// keep the reconcile short, a failing verification is retried on the next pass
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			// the pod serves the certificate of the service, not of its address
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+func verificationURL(scheme string, host string, port int32, path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(int(port))) + path
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
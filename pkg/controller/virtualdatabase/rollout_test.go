@@ -0,0 +1,134 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func blueGreenVdb() *v1alpha1.VirtualDatabase {
+	vdb := testVdb()
+	vdb.Spec.Rollout = &v1alpha1.RolloutObject{
+		Strategy:  v1alpha1.RolloutBlueGreen,
+		BlueGreen: &v1alpha1.BlueGreenObject{RetentionPeriod: "30m"},
+	}
+	vdb.Status.Version = "2"
+	return vdb
+}
+
+func TestVersionDeploymentName(t *testing.T) {
+	vdb := blueGreenVdb()
+	assert.Equal(t, "dv-v2", versionDeploymentName(vdb, "2"))
+	assert.Equal(t, "dv-v1.0-snapshot", versionDeploymentName(vdb, "1.0-SNAPSHOT"))
+	assert.Equal(t, "dv", versionDeploymentName(vdb, ""))
+}
+
+func TestServiceSelector(t *testing.T) {
+	vdb := blueGreenVdb()
+	assert.Equal(t, matchLabels("dv"), serviceSelector(vdb))
+	assert.Equal(t, "2", servingVersion(vdb))
+
+	vdb.Status.ActiveVersion = "1"
+	assert.Equal(t, "1", serviceSelector(vdb)[deploymentVersionLabel])
+	assert.Equal(t, "1", servingVersion(vdb))
+	assert.Equal(t, "1", buildService(vdb, true).Spec.Selector[deploymentVersionLabel])
+
+	vdb.Spec.Rollout.Strategy = v1alpha1.RolloutRolling
+	assert.Equal(t, matchLabels("dv"), serviceSelector(vdb))
+}
+
+func TestApplyVersion(t *testing.T) {
+	vdb := blueGreenVdb()
+	labels := matchLabels("dv")
+	dc := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "dv", Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: matchLabels("dv")},
+		},
+	}
+	dc.Spec.Template.Labels = labels
+	applyVersion(vdb, &dc)
+
+	assert.Equal(t, "dv-v2", dc.Name)
+	assert.Equal(t, "2", dc.Labels[deploymentVersionLabel])
+	assert.Equal(t, "2", dc.Spec.Template.Labels[deploymentVersionLabel])
+	assert.Equal(t, "2", dc.Spec.Selector.MatchLabels[deploymentVersionLabel])
+	_, ok := labels[deploymentVersionLabel]
+	assert.False(t, ok)
+}
+
+func TestSwitchVersion(t *testing.T) {
+	vdb := blueGreenVdb()
+	now := time.Now()
+
+	// first switch away from the Deployment of the Rolling strategy
+	assert.Nil(t, switchVersion(vdb, "2", now))
+	assert.Equal(t, "2", vdb.Status.ActiveVersion)
+	assert.Equal(t, "", vdb.Status.PreviousVersion)
+	assert.Equal(t, []string{"dv-v2", "dv"}, retainedDeployments(vdb, now))
+
+	vdb.Status.Version = "3"
+	assert.Nil(t, switchVersion(vdb, "3", now))
+	assert.Equal(t, "3", vdb.Status.ActiveVersion)
+	assert.Equal(t, "2", vdb.Status.PreviousVersion)
+	assert.Equal(t, now.Add(30*time.Minute).Unix(), vdb.Status.PreviousVersionExpiry.Unix())
+
+	// a rebuild of the same version keeps the previous one
+	assert.Nil(t, switchVersion(vdb, "3", now.Add(time.Minute)))
+	assert.Equal(t, "2", vdb.Status.PreviousVersion)
+
+	assert.Equal(t, []string{"dv-v3", "dv-v2"}, retainedDeployments(vdb, now.Add(29*time.Minute)))
+	assert.Equal(t, []string{"dv-v3"}, retainedDeployments(vdb, now.Add(31*time.Minute)))
+}
+
+func TestValidateRollout(t *testing.T) {
+	vdb := blueGreenVdb()
+	assert.Nil(t, validateRollout(vdb))
+
+	vdb.Spec.Rollout.BlueGreen.RetentionPeriod = "a day"
+	assert.NotNil(t, validateRollout(vdb))
+
+	vdb.Spec.Rollout.BlueGreen = nil
+	period, _ := retentionPeriod(vdb)
+	assert.Equal(t, time.Hour, period)
+
+	vdb.Spec.BufferStorage = &v1alpha1.BufferStorageObject{Type: v1alpha1.BufferStoragePerReplica, Size: "1Gi"}
+	assert.NotNil(t, validateRollout(vdb))
+}
+
+func TestVerificationURL(t *testing.T) {
+	assert.Equal(t, "https://10.0.0.1:8443/odata/p/C?$top=1", verificationURL("https", "10.0.0.1", 8443, "odata/p/C?$top=1"))
+	assert.Equal(t, "http://[fd00::1]:8080/odata/p/C", verificationURL("http", "fd00::1", 8080, "/odata/p/C"))
+}
+
+func TestRetryVerification(t *testing.T) {
+	vdb := blueGreenVdb()
+	now := time.Now()
+	assert.True(t, retryVerification(vdb, now))
+	assert.Equal(t, now.Unix(), vdb.Status.VerificationFailed.Unix())
+	assert.True(t, retryVerification(vdb, now.Add(verificationTimeout-time.Second)))
+
+	assert.False(t, retryVerification(vdb, now.Add(verificationTimeout)))
+	assert.Nil(t, vdb.Status.VerificationFailed)
+}
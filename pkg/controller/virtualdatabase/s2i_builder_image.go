@@ -19,9 +19,11 @@ package virtualdatabase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
-	"strings"
+	"reflect"
 
 	obuildv1 "github.com/openshift/api/build/v1"
 	scheme "github.com/openshift/client-go/build/clientset/versioned/scheme"
@@ -35,9 +37,11 @@ import (
 	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -62,6 +66,8 @@ func (action *s2iBuilderImageAction) CanHandle(vdb *v1alpha1.VirtualDatabase) bo
 
 // Handle handles the virtualdatabase
 func (action *s2iBuilderImageAction) Handle(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	namespace := builderNamespace(vdb)
+	output := builderImage(vdb)
 
 	if vdb.Status.Phase == v1alpha1.ReconcilerPhaseS2IReady {
 
@@ -69,6 +75,8 @@ func (action *s2iBuilderImageAction) Handle(ctx context.Context, vdb *v1alpha1.V
 		opDeploymentNS := os.Getenv("WATCH_NAMESPACE")
 		opDeploymentName := os.Getenv("OPERATOR_NAME")
 		r.client.Get(ctx, types.NamespacedName{Namespace: opDeploymentNS, Name: opDeploymentName}, opDeployment)
+		// owner references can not cross namespaces, a shared builder outlives the operator
+		owned := opDeployment.ObjectMeta.Namespace == namespace
 
 		log.Info("Building Base builder Image")
 		// Define new BuildConfig objects
@@ -77,7 +85,7 @@ func (action *s2iBuilderImageAction) Handle(ctx context.Context, vdb *v1alpha1.V
 			return err
 		}
 		// set ownerreference for service BC only
-		if _, err := image.EnsureImageStream(buildConfig.Name, vdb.ObjectMeta.Namespace, true, opDeployment, r.imageClient, r.client.GetScheme()); err != nil {
+		if _, err := image.EnsureImageStream(buildConfig.Name, namespace, owned, opDeployment, r.imageClient, r.client.GetScheme()); err != nil {
 			return err
 		}
 
@@ -98,9 +106,11 @@ func (action *s2iBuilderImageAction) Handle(ctx context.Context, vdb *v1alpha1.V
 			log.Info("Creating a new BuildConfig ", buildConfig.Name, " in namespace ", buildConfig.Namespace)
 
 			// make the Operator as the owner
-			err := controllerutil.SetControllerReference(opDeployment, &buildConfig, r.client.GetScheme())
-			if err != nil {
-				log.Error(err)
+			if owned {
+				err := controllerutil.SetControllerReference(opDeployment, &buildConfig, r.client.GetScheme())
+				if err != nil {
+					log.Error(err)
+				}
 			}
 
 			bc, err = r.buildClient.BuildConfigs(buildConfig.Namespace).Create(&buildConfig)
@@ -113,58 +123,201 @@ func (action *s2iBuilderImageAction) Handle(ctx context.Context, vdb *v1alpha1.V
 
 		log.Info("Created BuildConfig")
 
-		// Trigger first build of "builder" and binary BCs
-		if bc.Status.LastVersion == 0 {
-			log.Info("triggering the base builder image build")
+		if err := ensureImagePuller(ctx, vdb, r); err != nil {
+			return err
+		}
+
+		// the builds download through the Maven cache, wait for it to be ready
+		ready, err := ensureMavenCache(ctx, vdb, r)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			log.Info("Waiting for the Maven cache ", mavenCacheName, " in namespace ", namespace)
+			return nil
+		}
+
+		exists, err := imageStreamTagExists(namespace, output.Name, r)
+		if err != nil {
+			return err
+		}
+		builds, err := getBuilds(namespace, r)
+		if err != nil {
+			return err
+		}
+
+		// build the builder image for the versions of this operator, unless built already or by another vdb, and
+		// also when the previous build failed
+		if !exists && !isBuildInProgress(builds, output.Name) {
+			if bc.Spec.Output.To == nil || bc.Spec.Output.To.Name != output.Name {
+				bc.Spec = buildConfig.Spec
+				if bc, err = r.buildClient.BuildConfigs(namespace).Update(bc); err != nil {
+					return err
+				}
+			}
+			log.Info("triggering the base builder image build ", output.Name)
 			mavenRepos := constants.GetMavenRepositories(vdb)
 			if err = action.triggerBuild(ctx, *bc, mavenRepos, r); err != nil {
 				return err
 			}
-		} else {
-			// if in case nay previous build failed try again
-			builds, err := getBuilds(vdb, r)
-			if err != nil {
-				return err
-			}
-			for _, build := range builds.Items {
-				if build.Status.Phase == obuildv1.BuildPhaseError || build.Status.Phase == obuildv1.BuildPhaseFailed || build.Status.Phase == obuildv1.BuildPhaseCancelled {
-					log.Info("triggering the base builder image build")
-					mavenRepos := constants.GetMavenRepositories(vdb)
-					if err = action.triggerBuild(ctx, *bc, mavenRepos, r); err != nil {
-						return err
-					}
-				}
-			}
 		}
 		vdb.Status.Phase = v1alpha1.ReconcilerPhaseBuilderImage
 	} else if vdb.Status.Phase == v1alpha1.ReconcilerPhaseBuilderImage {
-		builds, err := getBuilds(vdb, r)
+		exists, err := imageStreamTagExists(namespace, output.Name, r)
 		if err != nil {
 			return err
 		}
-		for _, build := range builds.Items {
-			// set status of the build
-			if build.Status.Phase == obuildv1.BuildPhaseComplete && vdb.Status.Phase != v1alpha1.ReconcilerPhaseBuilderImageFinished {
-				vdb.Status.Phase = v1alpha1.ReconcilerPhaseBuilderImageFinished
-			} else if (build.Status.Phase == obuildv1.BuildPhaseError ||
-				build.Status.Phase == obuildv1.BuildPhaseFailed ||
-				build.Status.Phase == obuildv1.BuildPhaseCancelled) && vdb.Status.Phase != v1alpha1.ReconcilerPhaseBuilderImageFailed {
-				vdb.Status.Phase = v1alpha1.ReconcilerPhaseBuilderImageFailed
-			} else if build.Status.Phase == obuildv1.BuildPhaseRunning && vdb.Status.Phase != v1alpha1.ReconcilerPhaseBuilderImage {
-				vdb.Status.Phase = v1alpha1.ReconcilerPhaseBuilderImage
-			}
+		if exists {
+			vdb.Status.Phase = v1alpha1.ReconcilerPhaseBuilderImageFinished
+			return nil
+		}
+
+		builds, err := getBuilds(namespace, r)
+		if err != nil {
+			return err
+		}
+		if !isBuildInProgress(builds, output.Name) && isBuildFailed(builds, output.Name) {
+			vdb.Status.Phase = v1alpha1.ReconcilerPhaseBuilderImageFailed
 		}
 	}
 	return nil
 }
 
-func getBuilds(vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (*obuildv1.BuildList, error) {
+// builderNamespace is the namespace of the builder image, the one shared by all namespaces when configured
+func builderNamespace(vdb *v1alpha1.VirtualDatabase) string {
+	if constants.Config.BuilderNamespace != "" {
+		return constants.Config.BuilderNamespace
+	}
+	return vdb.ObjectMeta.Namespace
+}
+
+// builderNamespaceAPI reads and writes the objects of the builder namespace. The cache of the manager only holds
+// the watched namespace, so these requests go to the api server directly
+func builderNamespaceAPI(r *ReconcileVirtualDatabase) kubernetes.Interface {
+	return r.client
+}
+
+// builderTag is derived from the versions the builder image warms the Maven repository with, so that a changed
+// configuration or a new operator builds a new builder image
+func builderTag() string {
+	bi := constants.Config.BuildImage
+	hash := sha256.New()
+	for _, v := range []string{constants.Version, constants.Config.TeiidSpringBootVersion, constants.Config.TeiidVersion,
+		constants.Config.SpringBootVersion, bi.Registry, bi.ImagePrefix, bi.ImageName, bi.Tag} {
+		hash.Write([]byte(v))
+		hash.Write([]byte{0})
+	}
+	return "v" + hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// builderImage is the ImageStreamTag of the builder image the service images are built from
+func builderImage(vdb *v1alpha1.VirtualDatabase) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Name:      constants.BuilderImageTargetName + ":" + builderTag(),
+		Namespace: builderNamespace(vdb),
+		Kind:      "ImageStreamTag",
+	}
+}
+
+// ensureImagePuller lets the builds and pods of the namespace of the vdb pull the images of the shared builder
+// namespace
+func ensureImagePuller(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	desired := buildImagePullerRoleBinding(vdb)
+	if desired.Namespace == vdb.ObjectMeta.Namespace {
+		return nil
+	}
+
+	bindings := builderNamespaceAPI(r).RbacV1().RoleBindings(desired.Namespace)
+	existing, err := bindings.Get(desired.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err = bindings.Create(&desired); err != nil {
+			return err
+		}
+		log.Info("RoleBinding created:", desired.Namespace, "/", desired.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(existing.RoleRef, desired.RoleRef) || !reflect.DeepEqual(existing.Subjects, desired.Subjects) {
+		// the role of a binding can not be changed, replace it
+		if err = bindings.Delete(desired.Name, &metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		if _, err = bindings.Create(&desired); err != nil {
+			return err
+		}
+		log.Info("RoleBinding replaced:", desired.Namespace, "/", desired.Name)
+	}
+	return nil
+}
+
+func buildImagePullerRoleBinding(vdb *v1alpha1.VirtualDatabase) rbacv1.RoleBinding {
+	return rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "teiid-image-puller-" + vdb.ObjectMeta.Namespace,
+			Namespace: builderNamespace(vdb),
+			Labels: map[string]string{
+				"app": constants.BuilderImageTargetName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "system:image-puller",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "Group",
+				Name:     "system:serviceaccounts:" + vdb.ObjectMeta.Namespace,
+			},
+		},
+	}
+}
+
+func imageStreamTagExists(namespace string, name string, r *ReconcileVirtualDatabase) (bool, error) {
+	_, err := r.imageClient.ImageStreamTags(namespace).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// isBuildInProgress tells whether a build of the image is queued or running
+func isBuildInProgress(builds *obuildv1.BuildList, output string) bool {
+	for _, build := range builds.Items {
+		if buildOutput(build) == output && (build.Status.Phase == obuildv1.BuildPhaseNew ||
+			build.Status.Phase == obuildv1.BuildPhasePending || build.Status.Phase == obuildv1.BuildPhaseRunning) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBuildFailed(builds *obuildv1.BuildList, output string) bool {
+	for _, build := range builds.Items {
+		if buildOutput(build) == output && (build.Status.Phase == obuildv1.BuildPhaseError ||
+			build.Status.Phase == obuildv1.BuildPhaseFailed || build.Status.Phase == obuildv1.BuildPhaseCancelled) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildOutput(build obuildv1.Build) string {
+	if build.Spec.Output.To == nil {
+		return ""
+	}
+	return build.Spec.Output.To.Name
+}
+
+func getBuilds(namespace string, r *ReconcileVirtualDatabase) (*obuildv1.BuildList, error) {
 	builds := &obuildv1.BuildList{}
 	options := metav1.ListOptions{
-		FieldSelector: "metadata.namespace=" + vdb.ObjectMeta.Namespace,
+		FieldSelector: "metadata.namespace=" + namespace,
 		LabelSelector: "buildconfig=" + constants.BuilderImageTargetName,
 	}
-	builds, err := r.buildClient.Builds(vdb.ObjectMeta.Namespace).List(options)
+	builds, err := r.buildClient.Builds(namespace).List(options)
 	if err != nil {
 		return builds, err
 	}
@@ -194,9 +347,10 @@ func (action *s2iBuilderImageAction) buildBC(vdb *v1alpha1.VirtualDatabase, r *R
 	imageName := fmt.Sprintf("%s:%s", bi.ImageName, bi.Tag)
 	//isNamespace := vdb.ObjectMeta.Namespace
 	// check if the base image is found otherwise use from dockerhub, add to local images
-	if !image.CheckImageStream(bi.ImageName, vdb.ObjectMeta.Namespace, r.imageClient) {
+	namespace := builderNamespace(vdb)
+	if !image.CheckImageStream(bi.ImageName, namespace, r.imageClient) {
 		dockerImage := fmt.Sprintf("%s/%s/%s", bi.Registry, bi.ImagePrefix, bi.ImageName)
-		err := image.CreateImageStream(bi.ImageName, vdb.ObjectMeta.Namespace, dockerImage, bi.Tag, r.imageClient, r.client.GetScheme())
+		err := image.CreateImageStream(bi.ImageName, namespace, dockerImage, bi.Tag, r.imageClient, r.client.GetScheme())
 		if err != nil {
 			return bc, err
 		}
@@ -206,19 +360,19 @@ func (action *s2iBuilderImageAction) buildBC(vdb *v1alpha1.VirtualDatabase, r *R
 	bc = obuildv1.BuildConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      builderName,
-			Namespace: vdb.ObjectMeta.Namespace,
+			Namespace: namespace,
 		},
 	}
 	bc.SetGroupVersionKind(obuildv1.SchemeGroupVersion.WithKind("BuildConfig"))
 	bc.Spec.Source.Binary = &obuildv1.BinaryBuildSource{}
-	bc.Spec.Output.To = &corev1.ObjectReference{Name: strings.Join([]string{builderName, "latest"}, ":"), Kind: "ImageStreamTag"}
+	bc.Spec.Output.To = &corev1.ObjectReference{Name: builderImage(vdb).Name, Kind: "ImageStreamTag"}
 	bc.Spec.Strategy.Type = obuildv1.SourceBuildStrategyType
 	bc.Spec.Strategy.SourceStrategy = &obuildv1.SourceBuildStrategy{
 		Incremental: &incremental,
 		Env:         envs,
 		From: corev1.ObjectReference{
 			Name:      imageName,
-			Namespace: vdb.ObjectMeta.Namespace,
+			Namespace: namespace,
 			Kind:      "ImageStreamTag",
 		},
 	}
@@ -241,7 +395,7 @@ func (action *s2iBuilderImageAction) triggerBuild(ctx context.Context, bc obuild
 
 	files := map[string]string{}
 
-	pom, err := GenerateVdbPom(vdbCopy, vdbutil.ParseDataSourcesInfoFromDdl(vdbCopy.Spec.Build.Source.DDL), true, true, true)
+	pom, err := GenerateVdbPom(vdbCopy, vdbutil.ParseDataSourcesInfoFromDdl(vdbCopy.Spec.Build.Source.DDL), constants.ConnectionFactories, true, true, true)
 	if err != nil {
 		return err
 	}
@@ -255,21 +409,16 @@ func (action *s2iBuilderImageAction) triggerBuild(ctx context.Context, bc obuild
 	addCopyPlugIn(jarDependency, "jar", "app.jar", "/tmp", &pom)
 
 	addVdbCodeGenPlugIn(&pom, "/tmp/src/src/main/resources/teiid.ddl", false, "0")
+	// warm the cache with the plugins of the bill of materials too
+	addSBOMPlugIns(&pom)
 	pomContent, err := maven.EncodeXML(pom)
 	if err != nil {
 		return err
 	}
 	log.Debug(" Base Build Pom ", pomContent)
 
-	// build default maven repository
-	repositories := []maven.Repository{}
-	mavenRepos := constants.GetMavenRepositories(vdbCopy)
-	for k, v := range mavenRepos {
-		repositories = append(repositories, maven.NewRepository(v+"@id="+k))
-	}
-
 	// read the settings file
-	settingsContent, err := readMavenSettingsFile(ctx, vdbCopy, r, repositories)
+	settingsContent, err := readMavenSettingsFile(ctx, vdbCopy, r, configuredRepositories(vdbCopy))
 	if err != nil {
 		log.Debugf("Failed reading the settings.xml file for vdb %s", vdbCopy.ObjectMeta.Name)
 		return err
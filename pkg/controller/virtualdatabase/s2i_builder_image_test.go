@@ -0,0 +1,80 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"testing"
+
+	obuildv1 "github.com/openshift/api/build/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/controller/virtualdatabase/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuilderImage(t *testing.T) {
+	vdb := testVdb()
+	config := constants.Config
+	defer func() { constants.Config = config }()
+
+	tag := builderTag()
+	assert.Equal(t, "myproject", builderNamespace(vdb))
+	assert.Equal(t, "virtualdatabase-builder:"+tag, builderImage(vdb).Name)
+	assert.Equal(t, "myproject", builderImage(vdb).Namespace)
+	assert.Equal(t, tag, builderTag())
+
+	// a new version of teiid spring boot needs a new builder image
+	constants.Config.TeiidSpringBootVersion = "99.0.0"
+	assert.NotEqual(t, tag, builderTag())
+
+	constants.Config.BuilderNamespace = "teiid-builder"
+	assert.Equal(t, "teiid-builder", builderImage(vdb).Namespace)
+}
+
+func TestImagePullerRoleBinding(t *testing.T) {
+	vdb := testVdb()
+	config := constants.Config
+	defer func() { constants.Config = config }()
+	constants.Config.BuilderNamespace = "teiid-builder"
+
+	binding := buildImagePullerRoleBinding(vdb)
+	assert.Equal(t, "teiid-image-puller-myproject", binding.Name)
+	assert.Equal(t, "teiid-builder", binding.Namespace)
+	assert.Equal(t, "system:image-puller", binding.RoleRef.Name)
+	assert.Equal(t, "ClusterRole", binding.RoleRef.Kind)
+	assert.Equal(t, "system:serviceaccounts:myproject", binding.Subjects[0].Name)
+}
+
+func TestBuilderBuildPhases(t *testing.T) {
+	build := func(output string, phase obuildv1.BuildPhase) obuildv1.Build {
+		b := obuildv1.Build{}
+		b.Spec.Output.To = &corev1.ObjectReference{Name: output}
+		b.Status.Phase = phase
+		return b
+	}
+	builds := &obuildv1.BuildList{Items: []obuildv1.Build{
+		build("virtualdatabase-builder:latest", obuildv1.BuildPhaseRunning),
+		build("virtualdatabase-builder:v1", obuildv1.BuildPhaseFailed),
+	}}
+	assert.True(t, isBuildInProgress(builds, "virtualdatabase-builder:latest"))
+	assert.False(t, isBuildInProgress(builds, "virtualdatabase-builder:v1"))
+	assert.True(t, isBuildFailed(builds, "virtualdatabase-builder:v1"))
+	assert.False(t, isBuildFailed(builds, "virtualdatabase-builder:v2"))
+
+	builds.Items = append(builds.Items, build("virtualdatabase-builder:v1", obuildv1.BuildPhasePending))
+	assert.True(t, isBuildInProgress(builds, "virtualdatabase-builder:v1"))
+}
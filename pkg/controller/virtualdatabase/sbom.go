@@ -0,0 +1,244 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	obuildv1 "github.com/openshift/api/build/v1"
+	scheme "github.com/openshift/client-go/build/clientset/versioned/scheme"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	cycloneDXVersion = "2.5.3"
+	sbomBeginMarker  = "TEIID-SBOM-BEGIN"
+	sbomEndMarker    = "TEIID-SBOM-END"
+	// sbomLinePrefix prefix of the lines of the document printed by the concat task
+	sbomLinePrefix = "[concat] "
+	sbomKey        = "bom.json.gz"
+)
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// bom the parts of a CycloneDX document the summary is built from
+type bom struct {
+	Components   []bomComponent  `json:"components"`
+	Dependencies []bomDependency `json:"dependencies"`
+}
+
+type bomComponent struct {
+	BomRef  string `json:"bom-ref"`
+	Group   string `json:"group"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type bomDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func sbomConfigMapName(vdb *v1alpha1.VirtualDatabase, version string) string {
+	suffix := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(version), "-"), "-.")
+	return vdb.ObjectMeta.Name + "-sbom-v" + suffix
+}
+
+// recordSBOM stores the bill of materials the completed build printed in a ConfigMap, and summarizes it in the
+// history entry of the version. The entry records why when there is none
+func recordSBOM(ctx context.Context, vdb *v1alpha1.VirtualDatabase, build obuildv1.Build, r *ReconcileVirtualDatabase) error {
+	entry := findBuild(vdb, vdb.Status.Version)
+	if entry == nil {
+		return nil
+	}
+	if reason := sbomUnavailable(vdb); reason != "" {
+		entry.SBOMUnavailable = reason
+		return nil
+	}
+	if err := storeSBOM(ctx, vdb, entry, build, r); err != nil {
+		entry.SBOMUnavailable = "the bill of materials could not be read from build " + build.Name + ": " + err.Error()
+		return err
+	}
+	return nil
+}
+
+// sbomUnavailable tells why no bill of materials is generated for the vdb, empty when one is
+func sbomUnavailable(vdb *v1alpha1.VirtualDatabase) string {
+	if isFatJarBuild(vdb) {
+		// the jar is copied into the image as is, no maven build resolves its dependencies
+		return "the image runs the prebuilt jar " + vdb.Spec.Build.Source.Maven + ", which is not built by the operator"
+	}
+	return ""
+}
+
+func storeSBOM(ctx context.Context, vdb *v1alpha1.VirtualDatabase, entry *v1alpha1.BuildHistoryEntry, build obuildv1.Build,
+	r *ReconcileVirtualDatabase) error {
+
+	buildLog, err := r.buildClient.RESTClient().Get().
+		Namespace(build.Namespace).
+		Resource("builds").
+		Name(build.Name).
+		SubResource("log").
+		VersionedParams(&obuildv1.BuildLogOptions{}, scheme.ParameterCodec).
+		Do().
+		Raw()
+	if err != nil {
+		return err
+	}
+	document, err := extractSBOM(buildLog)
+	if err != nil {
+		return err
+	}
+	summary, err := summarizeSBOM(vdb, document)
+	if err != nil {
+		return err
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	if _, err = w.Write(document); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	summary.ConfigMap = sbomConfigMapName(vdb, vdb.Status.Version)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: summary.ConfigMap, Namespace: vdb.ObjectMeta.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.client, cm, func() error {
+		mergeMetadata(&cm.ObjectMeta, metav1.ObjectMeta{Labels: matchLabels(vdb.ObjectMeta.Name)})
+		cm.BinaryData = map[string][]byte{sbomKey: compressed.Bytes()}
+		return controllerutil.SetControllerReference(vdb, cm, r.client.GetScheme())
+	})
+	if err != nil {
+		return err
+	}
+	entry.SBOM = summary
+	log.Info("Bill of materials of version ", vdb.Status.Version, " stored in ConfigMap ", summary.ConfigMap)
+	return nil
+}
+
+func removeSBOM(ctx context.Context, vdb *v1alpha1.VirtualDatabase, version string, r *ReconcileVirtualDatabase) error {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: sbomConfigMapName(vdb, version), Namespace: vdb.ObjectMeta.Namespace}, cm)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(cm, vdb) {
+		return nil
+	}
+	return r.client.Delete(ctx, cm)
+}
+
+// extractSBOM reads the CycloneDX document printed between the markers from the build log
+func extractSBOM(buildLog []byte) ([]byte, error) {
+	var document bytes.Buffer
+	inside := false
+	scanner := bufio.NewScanner(bytes.NewReader(buildLog))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := ansiEscape.ReplaceAllString(scanner.Text(), "")
+		if strings.Contains(line, sbomBeginMarker) {
+			inside = true
+			document.Reset()
+			continue
+		}
+		if strings.Contains(line, sbomEndMarker) && inside {
+			if !json.Valid(document.Bytes()) {
+				return nil, errors.New("the bill of materials in the build log is not valid JSON")
+			}
+			return document.Bytes(), nil
+		}
+		if idx := strings.Index(line, sbomLinePrefix); inside && idx != -1 {
+			document.WriteString(line[idx+len(sbomLinePrefix):])
+			document.WriteString("\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("no bill of materials found in the build log")
+}
+
+// summarizeSBOM picks the resolved versions of teiid-spring-boot and Spring Boot, and the drivers of the data
+// sources: the dependencies declared in the vdb and the ones the spring-data modules of teiid pull in
+func summarizeSBOM(vdb *v1alpha1.VirtualDatabase, document []byte) (*v1alpha1.SBOMStatus, error) {
+	b := bom{}
+	if err := json.Unmarshal(document, &b); err != nil {
+		return nil, err
+	}
+	summary := &v1alpha1.SBOMStatus{Components: int32(len(b.Components))}
+
+	declared := map[string]bool{}
+	for _, str := range vdb.Spec.Build.Source.Dependencies {
+		if d, err := maven.ParseGAV(str); err == nil {
+			declared[d.GroupID+":"+d.ArtifactID] = true
+		}
+	}
+
+	components := map[string]bomComponent{}
+	for _, c := range b.Components {
+		components[c.BomRef] = c
+		switch c.Group + ":" + c.Name {
+		case "org.teiid:teiid-spring-boot-starter":
+			summary.TeiidSpringBootVersion = c.Version
+		case "org.springframework.boot:spring-boot":
+			summary.SpringBootVersion = c.Version
+		}
+	}
+
+	drivers := map[string]bool{}
+	for _, c := range b.Components {
+		if declared[c.Group+":"+c.Name] {
+			drivers[c.Group+":"+c.Name+":"+c.Version] = true
+		}
+	}
+	for _, d := range b.Dependencies {
+		module, ok := components[d.Ref]
+		if !ok || module.Group != "org.teiid" || !strings.HasPrefix(module.Name, "spring-data-") {
+			continue
+		}
+		for _, ref := range d.DependsOn {
+			c, ok := components[ref]
+			if ok && c.Group != "org.teiid" && !strings.HasPrefix(c.Group, "org.springframework") {
+				drivers[c.Group+":"+c.Name+":"+c.Version] = true
+			}
+		}
+	}
+	for d := range drivers {
+		summary.Drivers = append(summary.Drivers, d)
+	}
+	sort.Strings(summary.Drivers)
+	return summary, nil
+}
@@ -0,0 +1,101 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/maven"
+)
+
+const sbomDocument = `{
+  "bomFormat" : "CycloneDX",
+  "components" : [
+    {"bom-ref" : "pkg:maven/org.teiid/teiid-spring-boot-starter@1.6.0?type=jar", "group" : "org.teiid", "name" : "teiid-spring-boot-starter", "version" : "1.6.0"},
+    {"bom-ref" : "pkg:maven/org.springframework.boot/spring-boot@2.2.6.RELEASE?type=jar", "group" : "org.springframework.boot", "name" : "spring-boot", "version" : "2.2.6.RELEASE"},
+    {"bom-ref" : "pkg:maven/org.teiid/spring-data-postgresql@1.6.0?type=jar", "group" : "org.teiid", "name" : "spring-data-postgresql", "version" : "1.6.0"},
+    {"bom-ref" : "pkg:maven/org.postgresql/postgresql@42.2.5?type=jar", "group" : "org.postgresql", "name" : "postgresql", "version" : "42.2.5"},
+    {"bom-ref" : "pkg:maven/com.oracle/ojdbc8@19.3.0.0?type=jar", "group" : "com.oracle", "name" : "ojdbc8", "version" : "19.3.0.0"}
+  ],
+  "dependencies" : [
+    {"ref" : "pkg:maven/org.teiid/spring-data-postgresql@1.6.0?type=jar", "dependsOn" : [
+      "pkg:maven/org.postgresql/postgresql@42.2.5?type=jar",
+      "pkg:maven/org.springframework.boot/spring-boot@2.2.6.RELEASE?type=jar"
+    ]}
+  ]
+}`
+
+func TestExtractSBOM(t *testing.T) {
+	lines := []string{"[INFO] --- maven-antrun-plugin:3.0.0:run (print-sbom) @ dv ---",
+		"[INFO] Executing tasks",
+		"[WARNING]      [echo] TEIID-SBOM-BEGIN"}
+	for _, line := range strings.Split(sbomDocument, "\n") {
+		lines = append(lines, "\x1b[1;33m[WARNING]\x1b[m      [concat] "+line)
+	}
+	lines = append(lines, "[WARNING]      [echo] TEIID-SBOM-END", "[INFO] BUILD SUCCESS")
+
+	document, err := extractSBOM([]byte(strings.Join(lines, "\n")))
+	assert.Nil(t, err)
+	assert.Equal(t, sbomDocument+"\n", string(document))
+
+	_, err = extractSBOM([]byte("[INFO] BUILD SUCCESS"))
+	assert.NotNil(t, err)
+
+	_, err = extractSBOM([]byte("[echo] TEIID-SBOM-BEGIN\n[concat] {\n[echo] TEIID-SBOM-END"))
+	assert.NotNil(t, err)
+}
+
+func TestSummarizeSBOM(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.Build.Source.Dependencies = []string{"com.oracle:ojdbc8:19.3.0.0"}
+
+	summary, err := summarizeSBOM(vdb, []byte(sbomDocument))
+	assert.Nil(t, err)
+	assert.Equal(t, int32(5), summary.Components)
+	assert.Equal(t, "1.6.0", summary.TeiidSpringBootVersion)
+	assert.Equal(t, "2.2.6.RELEASE", summary.SpringBootVersion)
+	assert.Equal(t, []string{"com.oracle:ojdbc8:19.3.0.0", "org.postgresql:postgresql:42.2.5"}, summary.Drivers)
+
+	assert.Equal(t, "dv-sbom-v1.0-snapshot", sbomConfigMapName(vdb, "1.0-SNAPSHOT"))
+}
+
+func TestSBOMPlugIns(t *testing.T) {
+	project := createMavenProject("dv")
+	addSBOMPlugIns(&project)
+	pom, err := maven.EncodeXML(project)
+	assert.Nil(t, err)
+	assert.Contains(t, pom, "<artifactId>cyclonedx-maven-plugin</artifactId>")
+	assert.Contains(t, pom, "<outputFormat>json</outputFormat>")
+	assert.Contains(t, pom, `<target><echo message="TEIID-SBOM-BEGIN"/><concat><filelist dir="${project.build.directory}" files="bom.json"/></concat><echo message="TEIID-SBOM-END"/></target>`)
+}
+
+func TestSBOMUnavailable(t *testing.T) {
+	vdb := &v1alpha1.VirtualDatabase{}
+	vdb.Spec.Build.Source.Maven = "com.example:dv-customer:1.0"
+	assert.Contains(t, sbomUnavailable(vdb), "com.example:dv-customer:1.0")
+
+	vdb.Spec.Build.Source.Maven = "com.example:dv-customer:vdb:1.0"
+	assert.Equal(t, "", sbomUnavailable(vdb))
+
+	vdb.Spec.Build.Source.Maven = ""
+	vdb.Spec.Build.Source.DDL = "CREATE DATABASE customer;"
+	assert.Equal(t, "", sbomUnavailable(vdb))
+}
@@ -0,0 +1,256 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/envvar"
+	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
+	"github.com/teiid/teiid-operator/pkg/util/vdbutil"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isOIDCEnabled returns true when OpenID Connect security is configured on the VDB
+func isOIDCEnabled(vdb *v1alpha1.VirtualDatabase) bool {
+	return vdb.Spec.Security != nil && vdb.Spec.Security.OIDC != nil
+}
+
+// isSecurityEnabled returns true when the Keycloak based security module needs to be added to the VDB
+func isSecurityEnabled(vdb *v1alpha1.VirtualDatabase) bool {
+	if isOIDCEnabled(vdb) {
+		return true
+	}
+	// deprecated, configuration through the environment properties
+	if envvar.Get(vdb.Spec.Env, "KEYCLOAK_AUTH_SERVER_URL") != nil {
+		log.Warn("KEYCLOAK_AUTH_SERVER_URL environment property is deprecated, use spec.security.oidc instead")
+		return true
+	}
+	return false
+}
+
+// oidcAuthServerAndRealm splits the issuer URL into Keycloak auth server URL and realm
+func oidcAuthServerAndRealm(oidc *v1alpha1.OIDCObject) (string, string) {
+	authServerURL := strings.TrimSuffix(oidc.IssuerURL, "/")
+	realm := oidc.Realm
+	if idx := strings.LastIndex(authServerURL, "/realms/"); idx != -1 {
+		if realm == "" {
+			realm = authServerURL[idx+len("/realms/"):]
+		}
+		authServerURL = authServerURL[:idx]
+	}
+	return authServerURL, realm
+}
+
+// oidcProperties returns application.properties entries for Keycloak adapter
+func oidcProperties(vdb *v1alpha1.VirtualDatabase) []string {
+	if !isOIDCEnabled(vdb) {
+		return []string{}
+	}
+	oidc := vdb.Spec.Security.OIDC
+	authServerURL, realm := oidcAuthServerAndRealm(oidc)
+
+	props := []string{
+		"keycloak.auth-server-url=" + authServerURL,
+		"keycloak.realm=" + realm,
+		"keycloak.resource=" + oidc.ClientID,
+		"keycloak.bearer-only=true",
+		"keycloak.ssl-required=external",
+	}
+	if oidc.CredentialsSecretRef == nil {
+		props = append(props, "keycloak.public-client=true")
+	}
+	if oidc.RoleMapping != nil {
+		props = append(props, "keycloak.use-resource-role-mappings="+strconv.FormatBool(oidc.RoleMapping.UseResourceRoles))
+		if oidc.RoleMapping.PrincipalAttribute != "" {
+			props = append(props, "keycloak.principal-attribute="+oidc.RoleMapping.PrincipalAttribute)
+		}
+	}
+	return props
+}
+
+// oidcEnvironments returns the environment properties that carry the client secret, the secret
+// is never written into the image
+func oidcEnvironments(vdb *v1alpha1.VirtualDatabase) []corev1.EnvVar {
+	envs := []corev1.EnvVar{}
+	if isOIDCEnabled(vdb) && vdb.Spec.Security.OIDC.CredentialsSecretRef != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name: "KEYCLOAK_CREDENTIALS_SECRET",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: vdb.Spec.Security.OIDC.CredentialsSecretRef,
+			},
+		})
+	}
+	return envs
+}
+
+// oidcSecretsExist checks that all the secrets referenced by the OIDC configuration are available
+func oidcSecretsExist(ctx context.Context, client k8sclient.Reader, vdb *v1alpha1.VirtualDatabase) bool {
+	if !isOIDCEnabled(vdb) {
+		return true
+	}
+	oidc := vdb.Spec.Security.OIDC
+	for _, ref := range []*corev1.SecretKeySelector{oidc.CredentialsSecretRef, oidc.CACertificateSecretRef} {
+		if ref == nil {
+			continue
+		}
+		if _, err := kubernetes.GetSecretRefValue(ctx, client, vdb.ObjectMeta.Namespace, ref); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// oidcCACertificate returns PEM encoded CA certificate of the identity provider if one configured
+func oidcCACertificate(ctx context.Context, client k8sclient.Reader, vdb *v1alpha1.VirtualDatabase) ([]byte, error) {
+	if !isOIDCEnabled(vdb) || vdb.Spec.Security.OIDC.CACertificateSecretRef == nil {
+		return nil, nil
+	}
+	cert, err := kubernetes.GetSecretRefValue(ctx, client, vdb.ObjectMeta.Namespace, vdb.Spec.Security.OIDC.CACertificateSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(cert), nil
+}
+
+var privileges = map[string]bool{
+	"SELECT":         true,
+	"INSERT":         true,
+	"UPDATE":         true,
+	"DELETE":         true,
+	"EXECUTE":        true,
+	"ALTER":          true,
+	"DROP":           true,
+	"ALL PRIVILEGES": true,
+}
+
+// validateDataRoles checks the data roles defined in the security section before anything is built. The schemas
+// the roles reference are checked when the DDL is in the spec, the DDL of a maven VDB is only read by the build
+func validateDataRoles(vdb *v1alpha1.VirtualDatabase) error {
+	if vdb.Spec.Security == nil {
+		return nil
+	}
+	if vdb.Spec.Build.Source.DDL != "" {
+		_, err := dataRolesDdl(vdb, vdb.Spec.Build.Source.DDL)
+		return err
+	}
+	for _, role := range vdb.Spec.Security.Roles {
+		if _, err := createRoleStatement(role); err != nil {
+			return err
+		}
+		for _, p := range role.Permissions {
+			if _, err := grantedPrivileges(role, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dataRolesDdl renders the data roles defined in the security section into DDL statements, the
+// schemas referenced must be defined in the supplied VDB DDL
+func dataRolesDdl(vdb *v1alpha1.VirtualDatabase, ddl string) (string, error) {
+	if vdb.Spec.Security == nil || len(vdb.Spec.Security.Roles) == 0 {
+		return "", nil
+	}
+
+	schemas := map[string]bool{}
+	for _, s := range vdbutil.ParseSchemaNamesFromDdl(ddl) {
+		schemas[s] = true
+	}
+
+	var statements []string
+	for _, role := range vdb.Spec.Security.Roles {
+		statement, err := createRoleStatement(role)
+		if err != nil {
+			return "", err
+		}
+		statements = append(statements, statement)
+
+		for _, p := range role.Permissions {
+			if !schemas[strings.ToLower(p.Schema)] {
+				return "", fmt.Errorf("schema %s referenced in data role %s is not defined in the VDB", p.Schema, role.Name)
+			}
+			grants, err := grantedPrivileges(role, p)
+			if err != nil {
+				return "", err
+			}
+
+			resource := "SCHEMA " + quoteIdentifier(p.Schema)
+			if p.View != "" {
+				resource = "TABLE " + quoteIdentifier(p.Schema) + "." + quoteIdentifier(p.View)
+			}
+			statements = append(statements, fmt.Sprintf("GRANT %s ON %s TO %s;", strings.Join(grants, ","), resource, quoteIdentifier(role.Name)))
+		}
+	}
+	return strings.Join(statements, "\n"), nil
+}
+
+func createRoleStatement(role v1alpha1.DataRoleObject) (string, error) {
+	if role.Name == "" {
+		return "", fmt.Errorf("data role name is required")
+	}
+	if role.AnyAuthenticated {
+		return fmt.Sprintf("CREATE ROLE %s WITH ANY AUTHENTICATED;", quoteIdentifier(role.Name)), nil
+	}
+	if len(role.Groups) == 0 {
+		return "", fmt.Errorf("data role %s must define groups or be assigned to any authenticated user", role.Name)
+	}
+	groups := make([]string, len(role.Groups))
+	for i, g := range role.Groups {
+		groups[i] = quoteIdentifier(g)
+	}
+	return fmt.Sprintf("CREATE ROLE %s WITH FOREIGN ROLE %s;", quoteIdentifier(role.Name), strings.Join(groups, ", ")), nil
+}
+
+// grantedPrivileges are the privileges of the permission, SELECT when none are listed
+func grantedPrivileges(role v1alpha1.DataRoleObject, p v1alpha1.PermissionObject) ([]string, error) {
+	if len(p.Privileges) == 0 {
+		return []string{"SELECT"}, nil
+	}
+	grants := []string{}
+	for _, privilege := range p.Privileges {
+		privilege = strings.ToUpper(strings.TrimSpace(privilege))
+		if !privileges[privilege] {
+			return nil, fmt.Errorf("invalid privilege %s in data role %s", privilege, role.Name)
+		}
+		grants = append(grants, privilege)
+	}
+	if len(grants) > 1 && privilegesContain(grants, "ALL PRIVILEGES") {
+		return nil, fmt.Errorf("ALL PRIVILEGES can not be combined with other privileges in data role %s", role.Name)
+	}
+	return grants, nil
+}
+
+func privilegesContain(grants []string, privilege string) bool {
+	for _, g := range grants {
+		if g == privilege {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdentifier(id string) string {
+	return "\"" + strings.Replace(id, "\"", "\"\"", -1) + "\""
+}
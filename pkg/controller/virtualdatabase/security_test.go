@@ -0,0 +1,125 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDataRolesDdl(t *testing.T) {
+	contents, _ := ioutil.ReadFile("../../../deploy/crs/vdb_from_rdbms.yaml")
+	var vdb v1alpha1.VirtualDatabase
+	err := yaml.Unmarshal(contents, &vdb)
+	assert.Nil(t, err)
+
+	ddl, err := dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.Nil(t, err)
+	assert.Equal(t, "", ddl)
+
+	vdb.Spec.Security = &v1alpha1.SecurityObject{
+		Roles: []v1alpha1.DataRoleObject{
+			{
+				Name:   "readOnly",
+				Groups: []string{"analyst", "auditor"},
+				Permissions: []v1alpha1.PermissionObject{
+					{Schema: "portfolio", View: "CustomerZip"},
+				},
+			},
+			{
+				Name:   "admin",
+				Groups: []string{"dba"},
+				Permissions: []v1alpha1.PermissionObject{
+					{Schema: "accounts", Privileges: []string{"select", "insert", "update"}},
+				},
+			},
+		},
+	}
+	ddl, err = dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE ROLE "readOnly" WITH FOREIGN ROLE "analyst", "auditor";
+GRANT SELECT ON TABLE "portfolio"."CustomerZip" TO "readOnly";
+CREATE ROLE "admin" WITH FOREIGN ROLE "dba";
+GRANT SELECT,INSERT,UPDATE ON SCHEMA "accounts" TO "admin";`, ddl)
+
+	vdb.Spec.Security.Roles[1].Permissions[0].Schema = "missing"
+	_, err = dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.NotNil(t, err)
+
+	vdb.Spec.Security.Roles[1].Permissions[0].Schema = "accounts"
+	vdb.Spec.Security.Roles[1].Permissions[0].Privileges = []string{"truncate"}
+	_, err = dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.NotNil(t, err)
+
+	vdb.Spec.Security.Roles[1].Permissions[0].Privileges = []string{"all privileges", "select"}
+	_, err = dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.NotNil(t, err)
+
+	vdb.Spec.Security.Roles[1].Permissions[0].Privileges = []string{"all privileges"}
+	ddl, err = dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.Nil(t, err)
+	assert.Contains(t, ddl, `GRANT ALL PRIVILEGES ON SCHEMA "accounts" TO "admin";`)
+}
+
+func TestDataRolesDdlViewGrant(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{}
+	vdb.Spec.Build.Source.DDL = `CREATE DATABASE customer;
+	USE DATABASE customer;
+	CREATE VIRTUAL SCHEMA portfolio;`
+	vdb.Spec.Security = &v1alpha1.SecurityObject{
+		Roles: []v1alpha1.DataRoleObject{{
+			Name:             "reader",
+			AnyAuthenticated: true,
+			Permissions: []v1alpha1.PermissionObject{
+				{Schema: "portfolio", View: "Customer\"Zip", Privileges: []string{"select"}},
+			},
+		}},
+	}
+	ddl, err := dataRolesDdl(&vdb, vdb.Spec.Build.Source.DDL)
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE ROLE "reader" WITH ANY AUTHENTICATED;
+GRANT SELECT ON TABLE "portfolio"."Customer""Zip" TO "reader";`, ddl)
+}
+
+func TestValidateDataRoles(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{}
+	assert.Nil(t, validateDataRoles(&vdb))
+
+	vdb.Spec.Build.Source.Maven = "com.example:customer:vdb:1.0"
+	vdb.Spec.Security = &v1alpha1.SecurityObject{
+		Roles: []v1alpha1.DataRoleObject{{
+			Name:        "admin",
+			Groups:      []string{"dba"},
+			Permissions: []v1alpha1.PermissionObject{{Schema: "accounts", Privileges: []string{"all privileges", "select"}}},
+		}},
+	}
+	// the schemas of a maven VDB are not known yet, the privileges are
+	assert.NotNil(t, validateDataRoles(&vdb))
+	vdb.Spec.Security.Roles[0].Permissions[0].Privileges = []string{"all privileges"}
+	assert.Nil(t, validateDataRoles(&vdb))
+
+	vdb.Spec.Build.Source.Maven = ""
+	vdb.Spec.Build.Source.DDL = `CREATE DATABASE customer;
+	USE DATABASE customer;
+	CREATE VIRTUAL SCHEMA portfolio;`
+	assert.NotNil(t, validateDataRoles(&vdb))
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -114,7 +115,14 @@ func (action *serviceImageAction) buildServiceImage(ctx context.Context, vdb *v1
 
 	// Trigger first build of "builder" and binary BCs
 	if bc.Status.LastVersion == 0 || digest.Value != vdb.Status.Digest {
-		envvar.SetVal(&bc.Spec.Strategy.SourceStrategy.Env, "DIGEST", vdb.Status.Digest)
+		// refresh the build with the current version, builder image and Maven arguments
+		desired, err := action.newServiceBC(vdb)
+		if err != nil {
+			return err
+		}
+		bc.Spec.Output.To = desired.Spec.Output.To
+		bc.Spec.Strategy.SourceStrategy.From = desired.Spec.Strategy.SourceStrategy.From
+		bc.Spec.Strategy.SourceStrategy.Env = desired.Spec.Strategy.SourceStrategy.Env
 
 		if err := r.client.Update(ctx, bc); err != nil {
 			return err
@@ -179,6 +187,13 @@ func (action *serviceImageAction) monitorServiceImage(ctx context.Context, vdb *
 
 	// set status of the build
 	if build.Status.Phase == obuildv1.BuildPhaseComplete {
+		if err := recordServiceImage(ctx, vdb, build, r); err != nil {
+			return err
+		}
+		if err := recordSBOM(ctx, vdb, build, r); err != nil {
+			// the bill of materials is informational, do not hold back the deployment
+			log.Warn("Failed to store the bill of materials of build ", build.Name, " ", err)
+		}
 		vdb.Status.Phase = v1alpha1.ReconcilerPhaseServiceImageFinished
 	} else if build.Status.Phase == obuildv1.BuildPhaseError ||
 		build.Status.Phase == obuildv1.BuildPhaseFailed ||
@@ -221,8 +236,66 @@ func defaultBuildOptions() string {
 	return str
 }
 
+// serviceBuildOptions merges spec.build.maven into the default build options, its properties replace the defaults
+// of the same name
+func serviceBuildOptions(vdb *v1alpha1.VirtualDatabase) string {
+	m := vdb.Spec.Build.Maven
+	if m == nil {
+		return defaultBuildOptions()
+	}
+
+	properties := map[string]string{}
+	for k, v := range m.Properties {
+		properties[k] = v
+	}
+	if m.JDK != "" {
+		for _, k := range []string{"maven.compiler.source", "maven.compiler.target"} {
+			if _, ok := properties[k]; !ok {
+				properties[k] = m.JDK
+			}
+		}
+	}
+
+	options := []string{}
+	for _, option := range strings.Fields(defaultBuildOptions()) {
+		if strings.HasPrefix(option, "-D") {
+			if _, ok := properties[strings.SplitN(option[2:], "=", 2)[0]]; ok {
+				continue
+			}
+		}
+		options = append(options, option)
+	}
+
+	names := make([]string, 0, len(properties))
+	for k := range properties {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		if properties[k] == "" {
+			options = append(options, "-D"+k)
+		} else {
+			options = append(options, "-D"+k+"="+properties[k])
+		}
+	}
+
+	if len(m.Profiles) > 0 {
+		options = append(options, "-P"+strings.Join(m.Profiles, ","))
+	}
+	options = append(options, m.Args...)
+	return " " + strings.Join(options, " ")
+}
+
+// serviceBuilderImage is the image the service image is built with, the builder image of the operator unless
+// overridden in spec.build.maven
+func serviceBuilderImage(vdb *v1alpha1.VirtualDatabase) corev1.ObjectReference {
+	if vdb.Spec.Build.Maven != nil && vdb.Spec.Build.Maven.BuilderImage != "" {
+		return corev1.ObjectReference{Name: vdb.Spec.Build.Maven.BuilderImage, Kind: "DockerImage"}
+	}
+	return *builderImage(vdb)
+}
+
 func (action *serviceImageAction) newServiceBC(vdb *v1alpha1.VirtualDatabase) (obuildv1.BuildConfig, error) {
-	baseImage := strings.Join([]string{constants.BuilderImageTargetName, "latest"}, ":")
 
 	envs := envvar.Clone(vdb.Spec.Build.Env)
 
@@ -233,7 +306,12 @@ func (action *serviceImageAction) newServiceBC(vdb *v1alpha1.VirtualDatabase) (o
 		javaProperties = javaProperties + "-D" + k + "=" + v + " "
 	}
 
-	str := defaultBuildOptions()
+	str := serviceBuildOptions(vdb)
+
+	// arguments given in the environment of the build are kept, after the ones of the operator
+	if userArgs := envvar.Get(envs, "MAVEN_ARGS"); userArgs != nil && userArgs.Value != "" {
+		str = str + " " + userArgs.Value
+	}
 
 	// set it back original default
 	envvar.SetVal(&envs, "DEPLOYMENTS_DIR", "/deployments")
@@ -253,7 +331,7 @@ func (action *serviceImageAction) newServiceBC(vdb *v1alpha1.VirtualDatabase) (o
 		},
 	}
 	bc.SetGroupVersionKind(obuildv1.SchemeGroupVersion.WithKind("BuildConfig"))
-	bc.Spec.Output.To = &corev1.ObjectReference{Name: strings.Join([]string{vdb.ObjectMeta.Name, "latest"}, ":"), Kind: "ImageStreamTag"}
+	bc.Spec.Output.To = serviceImageOutput(vdb)
 
 	// for some reason "vdb.Spec.Build.Source" comes in as empty object rather than nil
 	// create the source build object
@@ -262,7 +340,7 @@ func (action *serviceImageAction) newServiceBC(vdb *v1alpha1.VirtualDatabase) (o
 	bc.Spec.Source.Binary = &obuildv1.BinaryBuildSource{}
 	bc.Spec.Strategy.Type = obuildv1.SourceBuildStrategyType
 	bc.Spec.Strategy.SourceStrategy = &obuildv1.SourceBuildStrategy{
-		From:        corev1.ObjectReference{Name: baseImage, Kind: "ImageStreamTag"},
+		From:        serviceBuilderImage(vdb),
 		ForcePull:   false,
 		Incremental: &inc,
 		Env:         envs,
@@ -345,15 +423,37 @@ func buildVdbBasedPayload(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r
 		log.Error("Data Source names are not in valid format ", err)
 		return files, err
 	}
+	err = validateTranslatorNames(vdb.Spec.DataSources, dataSourceInfos)
+	if err != nil {
+		log.Error("Translators of the Data Sources are not valid ", err)
+		return files, err
+	}
+
+	// add the data roles defined in the security section
+	rolesDdl, err := dataRolesDdl(vdb, ddlStr)
+	if err != nil {
+		log.Error("Data Roles are not valid ", err)
+		return files, err
+	}
+	if rolesDdl != "" {
+		ddlStr = ddlStr + "\n\n" + rolesDdl + "\n"
+	}
+
+	// source types of the operator and of the namespace
+	factories, err := connectionFactories(ctx, r, vdb.ObjectMeta.Namespace)
+	if err != nil {
+		return files, err
+	}
 
 	//Binary build, generate the pom file
-	pom, err := GenerateVdbPom(vdb, dataSourceInfos, false, addOpenAPI, vdbNeedsCacheStore)
+	pom, err := GenerateVdbPom(vdb, dataSourceInfos, factories, false, addOpenAPI, vdbNeedsCacheStore)
 	if err != nil {
 		return files, err
 	}
 	vdbFile := "teiid.vdb-file=teiid.ddl"
 
 	addVdbCodeGenPlugIn(&pom, "/tmp/src/src/main/resources/teiid.ddl", vdbNeedsCacheStore, vdb.Status.Version)
+	addSBOMPlugIns(&pom)
 	files["/src/main/resources/teiid.ddl"] = ddlStr
 
 	// if the materialization is in play then we have a new vdb file
@@ -368,14 +468,8 @@ func buildVdbBasedPayload(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r
 
 	log.Debugf("Pom file generated %s", pomContent)
 
-	// build default maven repository
-	repositories := []maven.Repository{}
-	mavenRepos := constants.GetMavenRepositories(vdb)
-	for k, v := range mavenRepos {
-		repositories = append(repositories, maven.NewRepository(v+"@id="+k))
-	}
 	// read the settings file
-	settingsContent, err := readMavenSettingsFile(ctx, vdb, r, repositories)
+	settingsContent, err := readMavenSettingsFile(ctx, vdb, r, configuredRepositories(vdb))
 	if err != nil {
 		log.Debugf("Failed reading the settings.xml file for vdb %s", vdb.ObjectMeta.Name)
 		return files, err
@@ -386,7 +480,7 @@ func buildVdbBasedPayload(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r
 	files["/configuration/settings.xml"] = settingsContent
 	files["/pom.xml"] = pomContent
 	files["/src/main/resources/prometheus-config.yml"] = PrometheusConfig(r.client, vdb.ObjectMeta.Namespace)
-	files["/src/main/resources/application.properties"] = applicationProperties(vdbFile, vdb.ObjectMeta.Name)
+	files["/src/main/resources/application.properties"] = applicationProperties(vdb, vdbFile)
 
 	return files, nil
 }
@@ -404,8 +498,15 @@ func (action *serviceImageAction) triggerBuild(bc obuildv1.BuildConfig, files ma
 		if err != nil {
 			return err
 		}
-		isName := buildConfig.Spec.Strategy.SourceStrategy.From.Name
-		_, err = r.imageClient.ImageStreamTags(buildConfig.Namespace).Get(isName, metav1.GetOptions{})
+		from := buildConfig.Spec.Strategy.SourceStrategy.From
+		isName := from.Name
+		isNamespace := from.Namespace
+		if isNamespace == "" {
+			isNamespace = buildConfig.Namespace
+		}
+		if from.Kind == "ImageStreamTag" {
+			_, err = r.imageClient.ImageStreamTags(isNamespace).Get(isName, metav1.GetOptions{})
+		}
 		if err != nil && apierr.IsNotFound(err) {
 			log.Warn(isName, " ImageStreamTag does not exist yet and is required for this build.")
 		} else if err != nil {
@@ -440,16 +541,12 @@ func (action *serviceImageAction) triggerBuild(bc obuildv1.BuildConfig, files ma
 	return nil
 }
 
-func applicationProperties(vdbProperty string, vdbName string) string {
-	str := strings.Join([]string{
+func applicationProperties(vdb *v1alpha1.VirtualDatabase, vdbProperty string) string {
+	props := []string{
 		"logging.level.io.jaegertracing.internal.reporters=WARN",
 		"logging.level.i.j.internal.reporters.LoggingReporter=WARN",
 		"logging.level.org.teiid.SECURITY=WARN",
 		"spring.main.allow-bean-definition-overriding=true",
-		"teiid.jdbc-secure-enable=true",
-		"teiid.pg-secure-enable=true",
-		"teiid.jdbc-enable=true",
-		"teiid.pg-enable=true",
 		"teiid.ssl.keyStoreType=pkcs12",
 		"teiid.ssl.keyStoreFileName=" + constants.KeystoreLocation + "/" + constants.KeystoreName,
 		"teiid.ssl.keyStorePassword=" + constants.KeystorePassword,
@@ -459,10 +556,12 @@ func applicationProperties(vdbProperty string, vdbName string) string {
 		"keycloak.truststore-password=" + constants.KeystorePassword,
 		"springfox.documentation.swagger.v2.path=/openapi.json",
 		"spring.teiid.model.package=io.integration",
-		"spring.application.name=" + vdbName,
+		"spring.application.name=" + vdb.ObjectMeta.Name,
 		"management.health.mongo.enabled=false",
 		"management.health.db.enabled=false",
 		vdbProperty,
-	}, "\n")
-	return str
+	}
+	props = append(props, protocolProperties(vdb)...)
+	props = append(props, oidcProperties(vdb)...)
+	return strings.Join(props, "\n")
 }
@@ -22,10 +22,92 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/envvar"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestApplicationsProperties(t *testing.T) {
-	assert.True(t, strings.Contains(applicationProperties("foo", "foo"), "management.health.mongo.enabled=false"))
-	assert.True(t, strings.Contains(applicationProperties("foo", "foo"), "management.health.db.enabled=false"))
-	assert.True(t, strings.Contains(applicationProperties("foo", "foo"), "spring.application.name=foo"))
+	vdb := v1alpha1.VirtualDatabase{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	assert.True(t, strings.Contains(applicationProperties(&vdb, "foo"), "management.health.mongo.enabled=false"))
+	assert.True(t, strings.Contains(applicationProperties(&vdb, "foo"), "management.health.db.enabled=false"))
+	assert.True(t, strings.Contains(applicationProperties(&vdb, "foo"), "spring.application.name=foo"))
+	assert.False(t, strings.Contains(applicationProperties(&vdb, "foo"), "keycloak.realm"))
+}
+
+func TestOIDCApplicationsProperties(t *testing.T) {
+	vdb := v1alpha1.VirtualDatabase{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	vdb.Spec.Security = &v1alpha1.SecurityObject{
+		OIDC: &v1alpha1.OIDCObject{
+			IssuerURL: "https://sso.example.com/auth/realms/teiid",
+			ClientID:  "portfolio",
+			CredentialsSecretRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "portfolio-oidc"},
+				Key:                  "secret",
+			},
+			RoleMapping: &v1alpha1.OIDCRoleMapping{
+				UseResourceRoles:   true,
+				PrincipalAttribute: "preferred_username",
+			},
+		},
+	}
+	props := applicationProperties(&vdb, "foo")
+	assert.True(t, strings.Contains(props, "keycloak.auth-server-url=https://sso.example.com/auth\n"))
+	assert.True(t, strings.Contains(props, "keycloak.realm=teiid"))
+	assert.True(t, strings.Contains(props, "keycloak.resource=portfolio"))
+	assert.True(t, strings.Contains(props, "keycloak.use-resource-role-mappings=true"))
+	assert.True(t, strings.Contains(props, "keycloak.principal-attribute=preferred_username"))
+	assert.False(t, strings.Contains(props, "keycloak.public-client"))
+	assert.False(t, strings.Contains(props, "portfolio-oidc"))
+
+	envs := oidcEnvironments(&vdb)
+	assert.Equal(t, 1, len(envs))
+	assert.Equal(t, "KEYCLOAK_CREDENTIALS_SECRET", envs[0].Name)
+	assert.Equal(t, "portfolio-oidc", envs[0].ValueFrom.SecretKeyRef.Name)
+}
+
+func TestServiceBuildOptions(t *testing.T) {
+	vdb := testVdb()
+	assert.Equal(t, defaultBuildOptions(), serviceBuildOptions(vdb))
+
+	vdb.Spec.Build.Maven = &v1alpha1.MavenBuildObject{
+		Args:       []string{"-U"},
+		Profiles:   []string{"internal-drivers", "native"},
+		Properties: map[string]string{"maven.compiler.target": "17", "skipITs": "", "internal.repo": "https://repo.example.com"},
+		JDK:        "11",
+	}
+	options := strings.Fields(serviceBuildOptions(vdb))
+	assert.Contains(t, options, "-Dmaven.compiler.source=11")
+	assert.Contains(t, options, "-Dmaven.compiler.target=17")
+	assert.NotContains(t, options, "-Dmaven.compiler.source=1.8")
+	assert.NotContains(t, options, "-Dmaven.compiler.target=1.8")
+	assert.Contains(t, options, "-DskipITs")
+	assert.Contains(t, options, "-Dinternal.repo=https://repo.example.com")
+	assert.Contains(t, options, "-DskipTests")
+	assert.Contains(t, options, "-Pinternal-drivers,native")
+	assert.Equal(t, "-U", options[len(options)-1])
+}
+
+func TestServiceBuildConfig(t *testing.T) {
+	vdb := testVdb()
+	vdb.Spec.Build.Env = []corev1.EnvVar{{Name: "MAVEN_ARGS", Value: "-Dcustom=true"}}
+	vdb.Spec.Build.Maven = &v1alpha1.MavenBuildObject{
+		Profiles:     []string{"internal-drivers"},
+		BuilderImage: "registry.access.redhat.com/ubi8/openjdk-17:1.11",
+	}
+	action := serviceImageAction{}
+	bc, err := action.newServiceBC(vdb)
+	assert.Nil(t, err)
+
+	args := envvar.Get(bc.Spec.Strategy.SourceStrategy.Env, "MAVEN_ARGS").Value
+	assert.True(t, strings.HasPrefix(args, "clean package "))
+	assert.True(t, strings.HasSuffix(args, "-Pinternal-drivers -Dcustom=true"))
+	assert.Equal(t, "DockerImage", bc.Spec.Strategy.SourceStrategy.From.Kind)
+	assert.Equal(t, "registry.access.redhat.com/ubi8/openjdk-17:1.11", bc.Spec.Strategy.SourceStrategy.From.Name)
+
+	vdb.Spec.Build.Maven = nil
+	bc, err = action.newServiceBC(vdb)
+	assert.Nil(t, err)
+	assert.Equal(t, "ImageStreamTag", bc.Spec.Strategy.SourceStrategy.From.Kind)
 }
@@ -0,0 +1,194 @@
+package virtualdatabase
+
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+import (
+	"context"
+
+	obuildv1 "github.com/openshift/api/build/v1"
+	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ensureStatefulSet creates the StatefulSet, or updates the image of the existing one. A Deployment the vdb ran
+// with before is removed by handleStatefulSet once the StatefulSet is ready
+func (action *deploymentAction) ensureStatefulSet(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	bc obuildv1.BuildConfig, r *ReconcileVirtualDatabase) error {
+
+	existing, err := findStatefulSet(vdb, r)
+	if k8serrors.IsNotFound(err) {
+		dc, err := action.buildDeployment(vdb, bc, r)
+		if err != nil {
+			return err
+		}
+		sts, err := buildStatefulSet(vdb, dc)
+		if err != nil {
+			return err
+		}
+		if err = r.client.Create(ctx, &sts); err != nil {
+			return err
+		}
+		log.Info("StatefulSet created:", sts.Name)
+	} else if err != nil {
+		return err
+	} else if setImage(&existing.Spec.Template.Spec.Containers[0], deploymentImage(vdb, bc)) {
+		// if a new image is created then update the statefulset with it
+		if err = r.client.Update(ctx, existing); err != nil {
+			log.Warn("Failed to update object. ", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// handleStatefulSet follows the rollout of the StatefulSet and keeps it in line with the vdb
+func (action *deploymentAction) handleStatefulSet(ctx context.Context, vdb *v1alpha1.VirtualDatabase,
+	r *ReconcileVirtualDatabase) error {
+
+	item, err := findStatefulSet(vdb, r)
+	if k8serrors.IsNotFound(err) {
+		// switched from a Deployment or removed, create it again
+		vdb.Status.Phase = v1alpha1.ReconcilerPhaseKeystoreCreated
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	updateStatefulSetReplicaStatus(vdb, *item)
+	if !isStatefulSetInReadyState(*item) {
+		return nil
+	}
+	if vdb.Status.Phase == v1alpha1.ReconcilerPhaseDeploying {
+		// the Deployment serves the clients until the replicas of the StatefulSet are ready
+		if err := removeDeployment(ctx, vdb, r); err != nil {
+			return err
+		}
+		log.Info("StatefulSet finished:" + vdb.ObjectMeta.Name)
+		vdb.Status.Phase = v1alpha1.ReconcilerPhaseRunning
+		return nil
+	}
+
+	update, err := action.syncPodTemplate(ctx, vdb, &item.ObjectMeta, &item.Spec.Replicas, &item.Spec.Template, r)
+	if err != nil {
+		return err
+	}
+	if update {
+		return r.client.Update(ctx, item)
+	}
+	return nil
+}
+
+// buildStatefulSet converts the generated Deployment into a StatefulSet with a buffer claim per replica. The claim
+// templates can not change once created, a new size only applies to a recreated StatefulSet
+func buildStatefulSet(vdb *v1alpha1.VirtualDatabase, dc appsv1.Deployment) (appsv1.StatefulSet, error) {
+	claimSpec, err := bufferClaimSpec(vdb)
+	if err != nil {
+		return appsv1.StatefulSet{}, err
+	}
+
+	sts := appsv1.StatefulSet{
+		ObjectMeta: dc.ObjectMeta,
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            dc.Spec.Replicas,
+			Selector:            dc.Spec.Selector,
+			Template:            dc.Spec.Template,
+			ServiceName:         vdb.ObjectMeta.Name,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   bufferVolumeName,
+						Labels: matchLabels(vdb.ObjectMeta.Name),
+					},
+					Spec: claimSpec,
+				},
+			},
+		},
+	}
+	sts.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+	return sts, nil
+}
+
+// updateStatefulSetReplicaStatus mirrors the replicas of the StatefulSet in the status, read by the scale subresource
+func updateStatefulSetReplicaStatus(vdb *v1alpha1.VirtualDatabase, sts appsv1.StatefulSet) {
+	vdb.Status.Replicas = sts.Status.Replicas
+	vdb.Status.ReadyReplicas = sts.Status.ReadyReplicas
+	vdb.Status.Selector = labels.SelectorFromSet(matchLabels(vdb.ObjectMeta.Name)).String()
+}
+
+// isStatefulSetInReadyState tells whether the StatefulSet observed its last change and all the replicas are ready
+func isStatefulSetInReadyState(sts appsv1.StatefulSet) bool {
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	return sts.Status.ObservedGeneration >= sts.Generation && sts.Status.ReadyReplicas >= replicas
+}
+
+func findStatefulSet(vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) (*appsv1.StatefulSet, error) {
+	obj := appsv1.StatefulSet{}
+	key := client.ObjectKey{Namespace: vdb.ObjectMeta.Namespace, Name: vdb.ObjectMeta.Name}
+	err := r.client.Get(context.TODO(), key, &obj)
+	return &obj, err
+}
+
+// removeStatefulSet removes the StatefulSet of the vdb, the claims of the replicas are kept
+func removeStatefulSet(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	sts, err := findStatefulSet(vdb, r)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(sts, vdb) {
+		return nil
+	}
+	if err = r.client.Delete(ctx, sts); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	log.Info("StatefulSet removed:", sts.Name)
+	return nil
+}
+
+// removeDeployment removes the Deployment of the vdb
+func removeDeployment(ctx context.Context, vdb *v1alpha1.VirtualDatabase, r *ReconcileVirtualDatabase) error {
+	dc, err := findDC(vdb, r)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(dc, vdb) {
+		return nil
+	}
+	if err = r.client.Delete(ctx, dc); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	log.Info("Deployment removed:", dc.Name)
+	return nil
+}
@@ -21,11 +21,12 @@ import (
 	"strconv"
 
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
+	"github.com/teiid/teiid-operator/pkg/util/conf"
 )
 
 // IsVdbUpdated --
-func IsVdbUpdated(vdb *v1alpha1.VirtualDatabase) bool {
-	digest, err := ComputeForVirtualDatabase(vdb)
+func IsVdbUpdated(vdb *v1alpha1.VirtualDatabase, factories map[string]conf.ConnectionFactory) bool {
+	digest, err := ComputeForVirtualDatabase(vdb, factories)
 	if err == nil {
 		return digest != vdb.Status.Digest
 	}
@@ -33,15 +34,16 @@ func IsVdbUpdated(vdb *v1alpha1.VirtualDatabase) bool {
 }
 
 // RedeployVdb Handle handles the virtualdatabase
-func RedeployVdb(vdb *v1alpha1.VirtualDatabase) error {
-	digest, _ := ComputeForVirtualDatabase(vdb)
+func RedeployVdb(vdb *v1alpha1.VirtualDatabase, factories map[string]conf.ConnectionFactory) error {
+	digest, _ := ComputeForVirtualDatabase(vdb, factories)
 	vdb.Status.Phase = v1alpha1.ReconcilerPhaseInitial
 	vdb.Status.Digest = digest
+	vdb.Status.VerificationFailed = nil
 
 	// we only want to update the version implicitly when the DDL based model is used
 	// for maven based it is expected of the user to change the version of maven to be reflected here
 	if vdb.Spec.Build.Source.DDL != "" && vdb.Spec.Build.Source.Version == "" {
-		ver, err := strconv.Atoi(vdb.Status.Version)
+		ver, err := strconv.Atoi(latestVersion(vdb))
 		if err == nil {
 			vdb.Status.Version = strconv.Itoa(ver + 1)
 		}
@@ -90,8 +90,12 @@ func (r *ReconcileVirtualDatabase) Reconcile(request reconcile.Request) (reconci
 		News2IBuilderImageAction(),
 		NewServiceImageAction(),
 		NewCreateServiceAction(),
+		NewNetworkPolicyAction(),
 		NewCreateCertificateAction(),
+		NewBufferStorageAction(),
 		NewDeploymentAction(),
+		NewAutoscalerAction(),
+		NewPodDisruptionBudgetAction(),
 		NewPrometheusMonitorAction(),
 	}
 
@@ -99,9 +103,22 @@ func (r *ReconcileVirtualDatabase) Reconcile(request reconcile.Request) (reconci
 	// have access to this
 	target := instance.DeepCopy()
 
-	// check if the VDB has been updated, then redo everything
-	if IsVdbUpdated(target) {
-		RedeployVdb(target)
+	// redeploy a previous build when requested, without rebuilding it
+	if IsRollbackRequested(target) {
+		RollbackVdb(target)
+		if err := r.client.Update(ctx, target); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// check if the VDB or the source types it uses have been updated, then redo everything
+	factories, err := connectionFactories(ctx, r, target.ObjectMeta.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if IsVdbUpdated(target, factories) {
+		RedeployVdb(target, factories)
 		if err := r.client.Update(ctx, target); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -18,6 +18,8 @@ limitations under the License.
 package virtualdatabase
 
 import (
+	"context"
+
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/client/versioned/typed/monitoring/v1"
 	oappsv1 "github.com/openshift/api/apps/v1"
 	obuildv1 "github.com/openshift/api/build/v1"
@@ -27,11 +29,15 @@ import (
 	imagev1 "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	teiidclient "github.com/teiid/teiid-operator/pkg/client"
+	"github.com/teiid/teiid-operator/pkg/util/kubernetes"
 	"github.com/teiid/teiid-operator/pkg/util/openshift"
 	otclient "github.com/teiid/teiid-operator/pkg/util/opentracing/client"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -112,6 +118,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		&obuildv1.Build{},
 		&oimagev1.ImageStream{},
 		&appsv1.Deployment{},
+		&appsv1.StatefulSet{},
 	}
 	objectHandler := &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -164,5 +171,42 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
+	// Watch for changes to the ConnectionFactory resources and requeue the VirtualDatabases of their namespace. The
+	// CRD is optional, without it only the source types of the operator are used
+	clients, err := clientset.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	if !kubernetes.HasServerResource(clients, v1alpha1.SchemeGroupVersion.String(), "ConnectionFactory") {
+		log.Warn("ConnectionFactory CRD is not installed, changes to ConnectionFactory resources are not watched " +
+			"until the operator is restarted")
+		return nil
+	}
+	err = c.Watch(&source.Kind{Type: &v1alpha1.ConnectionFactory{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(o handler.MapObject) []reconcile.Request {
+			return namespaceRequests(mgr.GetClient(), o.Meta.GetNamespace())
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// namespaceRequests returns a request for each VirtualDatabase of the namespace
+func namespaceRequests(c client.Reader, namespace string) []reconcile.Request {
+	list := &v1alpha1.VirtualDatabaseList{}
+	if err := c.List(context.TODO(), list, client.InNamespace(namespace)); err != nil {
+		log.Errorf("Error listing the VirtualDatabases of namespace %s: %v", namespace, err)
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, vdb := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      vdb.ObjectMeta.Name,
+			Namespace: vdb.ObjectMeta.Namespace,
+		}})
+	}
+	return requests
+}
@@ -18,6 +18,7 @@ limitations under the License.
 */
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"runtime"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/teiid/teiid-operator/pkg/util/logs"
 	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Configuration --
@@ -38,6 +40,9 @@ type Configuration struct {
 	BuildImage             BuildImage        `yaml:"buildImage,omitempty"`
 	Prometheus             PrometheusConfig  `yaml:"prometheus,omitempty"`
 	Labels                 map[string]string `yaml:"labels,omitempty"`
+	Resources              ResourcePolicy    `yaml:"resources,omitempty"`
+	BuilderNamespace       string            `yaml:"builderNamespace,omitempty"`
+	MavenCache             MavenCache        `yaml:"mavenCache,omitempty"`
 }
 
 // BuildImage --
@@ -53,6 +58,86 @@ type PrometheusConfig struct {
 	MatchLabels map[string]string `yaml:"matchLabels,omitempty"`
 }
 
+// MavenCache -- repository manager the builds download the Maven artifacts through, either an existing one at the
+// URL or a proxy deployed by the operator in the builder namespace. Without a builder namespace the proxy is
+// deployed in the namespace of every Virtual Database
+type MavenCache struct {
+	URL          string `yaml:"url,omitempty"`
+	MirrorOf     string `yaml:"mirrorOf,omitempty"`
+	Deploy       bool   `yaml:"deploy,omitempty"`
+	Image        string `yaml:"image,omitempty"`
+	Size         string `yaml:"size,omitempty"`
+	StorageClass string `yaml:"storageClass,omitempty"`
+}
+
+// ResourcePolicy -- default, minimum and maximum requests and limits of the Virtual Database container, keyed on
+// the resource name such as cpu or memory
+type ResourcePolicy struct {
+	DefaultRequests map[string]string `yaml:"defaultRequests,omitempty"`
+	DefaultLimits   map[string]string `yaml:"defaultLimits,omitempty"`
+	Min             map[string]string `yaml:"min,omitempty"`
+	Max             map[string]string `yaml:"max,omitempty"`
+}
+
+// Merge returns a policy with the defaults of the other policy taking precedence. The bounds of the other policy
+// can only tighten the bounds of this one, the larger minimum and the smaller maximum are kept
+func (p ResourcePolicy) Merge(other ResourcePolicy) (ResourcePolicy, error) {
+	min, err := mergeBounds(p.Min, other.Min, 1)
+	if err != nil {
+		return ResourcePolicy{}, err
+	}
+	max, err := mergeBounds(p.Max, other.Max, -1)
+	if err != nil {
+		return ResourcePolicy{}, err
+	}
+	return ResourcePolicy{
+		DefaultRequests: mergeMaps(p.DefaultRequests, other.DefaultRequests),
+		DefaultLimits:   mergeMaps(p.DefaultLimits, other.DefaultLimits),
+		Min:             min,
+		Max:             max,
+	}, nil
+}
+
+// mergeBounds keeps the bound of b where a has none or where it compares to the one of a as tighter
+func mergeBounds(a map[string]string, b map[string]string, tighter int) (map[string]string, error) {
+	merged := mergeMaps(a, nil)
+	for k, v := range b {
+		bound, err := resource.ParseQuantity(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bound %s of %s: %v", v, k, err)
+		}
+		if current, ok := a[k]; ok {
+			existing, err := resource.ParseQuantity(current)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bound %s of %s: %v", current, k, err)
+			}
+			if bound.Cmp(existing) != tighter {
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// ParseResourcePolicy reads a policy in the format of the resources section of the configuration
+func ParseResourcePolicy(data []byte) (ResourcePolicy, error) {
+	var p ResourcePolicy
+	err := yaml.Unmarshal(data, &p)
+	return p, err
+}
+
+func mergeMaps(a map[string]string, b map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range b {
+		merged[k] = v
+	}
+	return merged
+}
+
 // GetConfiguration --
 func GetConfiguration() Configuration {
 
@@ -89,6 +174,19 @@ func GetConfiguration() Configuration {
 		//registry.access.redhat.com/ubi8/openjdk-11:1.3
 		c.BuildImage = parseImage(os.Getenv("BUILD_IMAGE"))
 	}
+
+	if os.Getenv("MAVEN_MIRROR_URL") != "" {
+		c.MavenCache.URL = os.Getenv("MAVEN_MIRROR_URL")
+	}
+
+	if os.Getenv("BUILDER_NAMESPACE") != "" {
+		c.BuilderNamespace = os.Getenv("BUILDER_NAMESPACE")
+	}
+
+	if c.MavenCache.Deploy && c.MavenCache.URL == "" && c.BuilderNamespace == "" {
+		log.Warn("mavenCache.deploy is set without a builderNamespace, a Maven cache is deployed in every namespace " +
+			"with a Virtual Database. Set builderNamespace to share one cache between all of them")
+	}
 	return c
 }
 
@@ -33,3 +33,36 @@ func TestParserImage(t *testing.T) {
 
 	assert.Equal(t, bi, parseImage("registry.access.redhat.com/ubi8/openjdk-11:1.3"))
 }
+
+func TestResourcePolicy(t *testing.T) {
+	p, err := ParseResourcePolicy([]byte("defaultLimits:\n  memory: 1Gi\nmax:\n  cpu: \"2\"\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1Gi", p.DefaultLimits["memory"])
+
+	merged, err := GetConfiguration().Resources.Merge(p)
+	assert.Nil(t, err)
+	assert.Equal(t, "1Gi", merged.DefaultLimits["memory"])
+	assert.Equal(t, "1", merged.DefaultLimits["cpu"])
+	assert.Equal(t, "256Mi", merged.DefaultRequests["memory"])
+	assert.Equal(t, "2", merged.Max["cpu"])
+	assert.Equal(t, 0, len(merged.Min))
+}
+
+func TestResourcePolicyTightensBounds(t *testing.T) {
+	operator := ResourcePolicy{
+		Min: map[string]string{"memory": "256Mi"},
+		Max: map[string]string{"memory": "4Gi", "cpu": "2"},
+	}
+	merged, err := operator.Merge(ResourcePolicy{
+		Min: map[string]string{"memory": "128Mi", "cpu": "100m"},
+		Max: map[string]string{"memory": "2Gi", "cpu": "8"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "256Mi", merged.Min["memory"])
+	assert.Equal(t, "100m", merged.Min["cpu"])
+	assert.Equal(t, "2Gi", merged.Max["memory"])
+	assert.Equal(t, "2", merged.Max["cpu"])
+
+	_, err = operator.Merge(ResourcePolicy{Max: map[string]string{"cpu": "lots"}})
+	assert.NotNil(t, err)
+}
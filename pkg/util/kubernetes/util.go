@@ -23,6 +23,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/teiid/teiid-operator/pkg/apis/teiid/v1alpha1"
 	teiidclient "github.com/teiid/teiid-operator/pkg/client"
@@ -349,6 +351,59 @@ func HasServerGroup(client kubernetes.Interface, groupName string) bool {
 	return false
 }
 
+// discoveryCacheTTL how long the kinds served in a group version are cached, CRDs installed or removed in the
+// meantime are seen once it elapsed
+const discoveryCacheTTL = 5 * time.Minute
+
+type servedKinds struct {
+	kinds   map[string]bool
+	expires time.Time
+}
+
+var (
+	discoveryCache     = map[string]servedKinds{}
+	discoveryCacheLock sync.Mutex
+)
+
+// HasServerResource detects if the given kind is served by the server in the given group version, the discovery
+// is cached for discoveryCacheTTL as it is asked many times on each reconcile
+func HasServerResource(client kubernetes.Interface, groupVersion string, kind string) bool {
+	if client.Discovery() == nil {
+		log.Warnf("Tried to discover the platform, but no discovery API is available")
+		return false
+	}
+	kinds := cachedKinds(groupVersion, time.Now(), func() (map[string]bool, error) {
+		resources, err := client.Discovery().ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			return nil, err
+		}
+		kinds := map[string]bool{}
+		for _, r := range resources.APIResources {
+			kinds[r.Kind] = true
+		}
+		return kinds, nil
+	})
+	return kinds[kind]
+}
+
+// cachedKinds returns the kinds served in the group version, discovered with fetch when not cached or expired.
+// A group version that is not served is cached as well
+func cachedKinds(groupVersion string, now time.Time, fetch func() (map[string]bool, error)) map[string]bool {
+	discoveryCacheLock.Lock()
+	defer discoveryCacheLock.Unlock()
+
+	if cached, ok := discoveryCache[groupVersion]; ok && now.Before(cached.expires) {
+		return cached.kinds
+	}
+	kinds, err := fetch()
+	if err != nil {
+		log.Debugf("Group version %s is not served: %s", groupVersion, err)
+		kinds = map[string]bool{}
+	}
+	discoveryCache[groupVersion] = servedKinds{kinds: kinds, expires: now.Add(discoveryCacheTTL)}
+	return kinds
+}
+
 // CreateSecret --
 func CreateSecret(client teiidclient.Client, name, namespace string, owner metav1.Object, data map[string][]byte) error {
 	// build the secret with keystore and truststore
@@ -17,7 +17,9 @@ limitations under the License.
 package kubernetes
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -56,3 +58,28 @@ func TestValidateDataSourceNames(t *testing.T) {
 	}
 	assert.NotNil(t, ValidateEnvironmentPropertyNames(env))
 }
+
+func TestCachedKinds(t *testing.T) {
+	calls := 0
+	fetch := func() (map[string]bool, error) {
+		calls++
+		return map[string]bool{"Route": true}, nil
+	}
+	now := time.Now()
+	assert.True(t, cachedKinds("route.openshift.io/v1", now, fetch)["Route"])
+	assert.True(t, cachedKinds("route.openshift.io/v1", now.Add(time.Minute), fetch)["Route"])
+	assert.Equal(t, 1, calls)
+
+	// discovered again once expired
+	assert.True(t, cachedKinds("route.openshift.io/v1", now.Add(discoveryCacheTTL), fetch)["Route"])
+	assert.Equal(t, 2, calls)
+
+	// group versions that are not served are cached too
+	missing := func() (map[string]bool, error) {
+		calls++
+		return nil, errors.New("the server could not find the requested resource")
+	}
+	assert.False(t, cachedKinds("gateway.networking.k8s.io/v1", now, missing)["Gateway"])
+	assert.False(t, cachedKinds("gateway.networking.k8s.io/v1", now, missing)["Gateway"])
+	assert.Equal(t, 3, calls)
+}
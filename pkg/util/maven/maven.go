@@ -155,6 +155,141 @@ func DownloadDependency(d Dependency, filepath string, mavenRepos map[string]str
 	return "", errors.New("Failed to download the artifact from configured maven repositories")
 }
 
+// RemoteRepository -- repository the artifacts are resolved from, with the credentials of its server
+type RemoteRepository struct {
+	ID       string
+	URL      string
+	Username string
+	Password string
+}
+
+// NotFoundError -- every repository answered that the artifact does not exist
+type NotFoundError struct {
+	Artifact string
+}
+
+func (e NotFoundError) Error() string {
+	return "Artifact " + e.Artifact + " not found in the configured maven repositories"
+}
+
+// RemoteRepositories returns the repositories maven resolves from with the given settings and profiles: the
+// repositories of the active profiles and central, replaced by the mirror that matches them
+func RemoteRepositories(settings Settings, profiles []string) []RemoteRepository {
+	active := map[string]bool{}
+	for _, p := range profiles {
+		active[p] = true
+	}
+	if settings.ActiveProfiles != nil {
+		for _, p := range settings.ActiveProfiles.ActiveProfile {
+			active[p] = true
+		}
+	}
+
+	candidates := []Repository{}
+	for _, p := range settings.Profiles {
+		if p.Activation.ActiveByDefault || active[p.ID] {
+			candidates = append(candidates, p.Repositories...)
+		}
+	}
+	candidates = append(candidates, Repository{ID: "central", URL: "https://repo.maven.apache.org/maven2"})
+
+	repos := []RemoteRepository{}
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		id, url := c.ID, c.URL
+		if m := matchingMirror(settings, c); m != nil {
+			id, url = m.ID, m.URL
+		}
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		repo := RemoteRepository{ID: id, URL: url}
+		if settings.Servers != nil {
+			for _, server := range settings.Servers.Server {
+				if server.ID == id {
+					repo.Username = server.Username
+					repo.Password = server.Password
+				}
+			}
+		}
+		repos = append(repos, repo)
+	}
+	return repos
+}
+
+func matchingMirror(settings Settings, repo Repository) *Mirror {
+	if settings.Mirrors == nil {
+		return nil
+	}
+	for i, m := range settings.Mirrors.Mirror {
+		matched, excluded := false, false
+		for _, pattern := range strings.Split(m.MirrorOf, ",") {
+			switch pattern = strings.TrimSpace(pattern); {
+			case pattern == "!"+repo.ID:
+				excluded = true
+			case pattern == "*" || pattern == repo.ID:
+				matched = true
+			case pattern == "external:*" && !strings.Contains(repo.URL, "://localhost") && !strings.HasPrefix(repo.URL, "file:"):
+				matched = true
+			}
+		}
+		if matched && !excluded {
+			return &settings.Mirrors.Mirror[i]
+		}
+	}
+	return nil
+}
+
+// ResolveDependency checks that the artifact is available in one of the repositories without downloading it,
+// returns the URL the artifact was found at. A NotFoundError is returned when every repository answered that
+// the artifact does not exist, any other error means a repository could not be asked
+func ResolveDependency(d Dependency, repos []RemoteRepository, client *http.Client) (string, error) {
+	gav := d.GroupID + ":" + d.ArtifactID + ":" + d.Version
+	if d.Version == "" || d.Version == "?" {
+		return "", errors.New("Version of the artifact " + d.GroupID + ":" + d.ArtifactID + " is missing")
+	}
+
+	parts := append(strings.Split(d.GroupID, "."), d.ArtifactID)
+	artifactName := strings.Join(append(parts, d.Version), "/") + "/" + fileName(d, "")
+	if strings.Contains(d.Version, "SNAPSHOT") {
+		// the timestamped file name is listed in the metadata of the version
+		artifactName = strings.Join(append(parts, d.Version), "/") + "/maven-metadata.xml"
+	}
+
+	var failure error
+	for _, repo := range repos {
+		url := repo.URL + artifactName
+		if !strings.HasSuffix(repo.URL, "/") {
+			url = repo.URL + "/" + artifactName
+		}
+		req, err := http.NewRequest("HEAD", url, nil)
+		if err != nil {
+			return "", err
+		}
+		if repo.Username != "" {
+			req.SetBasicAuth(repo.Username, repo.Password)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			failure = err
+			continue
+		}
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return url, nil
+		case http.StatusNotFound, http.StatusGone:
+		default:
+			failure = fmt.Errorf("repository %s answered %s", repo.URL, resp.Status)
+		}
+	}
+	if failure != nil {
+		return "", errors.Wrap(failure, "Failed to resolve the artifact "+gav)
+	}
+	return "", NotFoundError{Artifact: gav}
+}
+
 func getSnapshotVersion(a Dependency, mavenRepos map[string]string) string {
 	for _, v := range mavenRepos {
 		url := v
@@ -117,6 +117,14 @@ type Configuration struct {
 	MaterializationEnable bool           `xml:"materializationEnable,omitempty"`
 	VdbVersion            string         `xml:"vdbVersion,omitempty"`
 	MaterializationType   string         `xml:"materializationType,omitempty"`
+	OutputFormat          string         `xml:"outputFormat,omitempty"`
+	OutputName            string         `xml:"outputName,omitempty"`
+	Target                *Target        `xml:"target,omitempty"`
+}
+
+// Target -- tasks of the maven-antrun-plugin
+type Target struct {
+	Tasks string `xml:",innerxml"`
 }
 
 // ArtifactItem --
@@ -139,11 +147,44 @@ type propertiesEntry struct {
 // Settings represent a maven settings
 type Settings struct {
 	XMLName           xml.Name
-	XMLNs             string    `xml:"xmlns,attr"`
-	XMLNsXsi          string    `xml:"xmlns:xsi,attr"`
-	XsiSchemaLocation string    `xml:"xsi:schemaLocation,attr"`
-	LocalRepository   string    `xml:"localRepository"`
-	Profiles          []Profile `xml:"profiles>profile,omitempty"`
+	XMLNs             string          `xml:"xmlns,attr"`
+	XMLNsXsi          string          `xml:"xmlns:xsi,attr"`
+	XsiSchemaLocation string          `xml:"xsi:schemaLocation,attr"`
+	LocalRepository   string          `xml:"localRepository"`
+	Servers           *Servers        `xml:"servers,omitempty"`
+	Mirrors           *Mirrors        `xml:"mirrors,omitempty"`
+	Profiles          []Profile       `xml:"profiles>profile,omitempty"`
+	ActiveProfiles    *ActiveProfiles `xml:"activeProfiles,omitempty"`
+}
+
+// Servers --
+type Servers struct {
+	Server []Server `xml:"server"`
+}
+
+// Server --
+type Server struct {
+	ID       string `xml:"id"`
+	Username string `xml:"username,omitempty"`
+	Password string `xml:"password,omitempty"`
+}
+
+// ActiveProfiles --
+type ActiveProfiles struct {
+	ActiveProfile []string `xml:"activeProfile"`
+}
+
+// Mirrors --
+type Mirrors struct {
+	Mirror []Mirror `xml:"mirror"`
+}
+
+// Mirror --
+type Mirror struct {
+	ID       string `xml:"id"`
+	Name     string `xml:"name,omitempty"`
+	URL      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
 }
 
 // Profile --
@@ -52,6 +52,13 @@ func NewDefaultSettings(repositories []Repository) Settings {
 	return settings
 }
 
+// ParseSettings --
+func ParseSettings(content string) (Settings, error) {
+	settings := Settings{}
+	err := xml.Unmarshal([]byte(content), &settings)
+	return settings, err
+}
+
 // CreateSettingsConfigMap --
 func CreateSettingsConfigMap(namespace string, name string, settings Settings) (*corev1.ConfigMap, error) {
 	data, err := EncodeXML(settings)
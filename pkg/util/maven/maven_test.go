@@ -19,7 +19,10 @@ package maven
 
 import (
 	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -275,3 +278,85 @@ func TestMetadata(t *testing.T) {
 	assert.Equal(t, "20200506095522", m.Versioning.SnapshotVersions[0].Updated)
 	assert.Equal(t, "1.0-20200506.095522-1", m.Versioning.SnapshotVersions[0].Value)
 }
+
+func TestResolveDependency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if user, password, ok := req.BasicAuth(); !ok || user != "deployer" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.Method == "HEAD" && req.URL.Path == "/maven2/org/postgresql/postgresql/42.2.14/postgresql-42.2.14.jar" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	repos := []RemoteRepository{{ID: "internal", URL: server.URL + "/maven2", Username: "deployer", Password: "secret"}}
+	client := &http.Client{Timeout: time.Second}
+
+	url, err := ResolveDependency(Dependency{GroupID: "org.postgresql", ArtifactID: "postgresql", Version: "42.2.14"}, repos, client)
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/maven2/org/postgresql/postgresql/42.2.14/postgresql-42.2.14.jar", url)
+
+	_, err = ResolveDependency(Dependency{GroupID: "org.postgresql", ArtifactID: "postgresql", Version: "42.2.99"}, repos, client)
+	assert.IsType(t, NotFoundError{}, err)
+
+	_, err = ResolveDependency(Dependency{GroupID: "org.postgresql", ArtifactID: "postgresql"}, repos, client)
+	assert.NotNil(t, err)
+
+	// without the credentials the repository can not tell
+	repos[0].Password = ""
+	_, err = ResolveDependency(Dependency{GroupID: "org.postgresql", ArtifactID: "postgresql", Version: "42.2.14"}, repos, client)
+	assert.NotNil(t, err)
+	assert.False(t, isNotFound(err))
+
+	// an unreachable repository is not a missing artifact
+	server.Close()
+	_, err = ResolveDependency(Dependency{GroupID: "org.postgresql", ArtifactID: "postgresql", Version: "42.2.99"}, repos, client)
+	assert.NotNil(t, err)
+	assert.False(t, isNotFound(err))
+}
+
+func isNotFound(err error) bool {
+	_, ok := err.(NotFoundError)
+	return ok
+}
+
+func TestRemoteRepositories(t *testing.T) {
+	settings, err := ParseSettings(`<settings>
+  <servers>
+    <server><id>nexus</id><username>deployer</username><password>secret</password></server>
+  </servers>
+  <mirrors>
+    <mirror><id>nexus</id><url>https://nexus.example.com/repository/maven-public/</url><mirrorOf>*,!vendor</mirrorOf></mirror>
+  </mirrors>
+  <profiles>
+    <profile>
+      <id>default</id>
+      <activation><activeByDefault>true</activeByDefault></activation>
+      <repositories><repository><id>jboss</id><url>https://repository.jboss.org/nexus/content/groups/public</url></repository></repositories>
+    </profile>
+    <profile>
+      <id>vendor</id>
+      <repositories><repository><id>vendor</id><url>https://maven.vendor.example.com/releases</url></repository></repositories>
+    </profile>
+  </profiles>
+</settings>`)
+	assert.Nil(t, err)
+
+	repos := RemoteRepositories(settings, nil)
+	assert.Equal(t, []RemoteRepository{
+		{ID: "nexus", URL: "https://nexus.example.com/repository/maven-public/", Username: "deployer", Password: "secret"},
+	}, repos)
+
+	repos = RemoteRepositories(settings, []string{"vendor"})
+	assert.Equal(t, 2, len(repos))
+	assert.Equal(t, RemoteRepository{ID: "vendor", URL: "https://maven.vendor.example.com/releases"}, repos[1])
+
+	repos = RemoteRepositories(NewDefaultSettings([]Repository{NewRepository("https://repo.example.com/maven2@id=example")}), nil)
+	assert.Equal(t, []RemoteRepository{
+		{ID: "example", URL: "https://repo.example.com/maven2"},
+		{ID: "central", URL: "https://repo.maven.apache.org/maven2"},
+	}, repos)
+}
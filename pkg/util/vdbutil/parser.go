@@ -73,6 +73,25 @@ func ParseDataSourcesInfoFromDdl(ddl string) []DatasourceInfo {
 	return sources
 }
 
+// ParseSchemaNamesFromDdl --
+func ParseSchemaNamesFromDdl(ddl string) []string {
+	var schemas []string
+	id := "(\\w+|(?:\"[^\"]*\"))"
+	commentOrSpace := "(/\\*([^*]|\\*[^/])*\\*/|--[^\r\n]*[\r\n]|\\s)+"
+	schemaRegEx := "^CREATE" + commentOrSpace + "(VIRTUAL" + commentOrSpace + ")?SCHEMA" + commentOrSpace + id
+
+	compiledSchemaRegEx := regexp.MustCompile(schemaRegEx)
+
+	lines := Tokenize(ddl)
+	for _, line := range lines {
+		line = strings.ToUpper(line)
+		if match := compiledSchemaRegEx.FindStringSubmatch(line); match != nil {
+			schemas = append(schemas, stripQuotes(match[len(match)-1]))
+		}
+	}
+	return schemas
+}
+
 // ShouldMaterialize --
 func ShouldMaterialize(ddl string) bool {
 	commentOrSpace := "(/\\*([^*]|\\*[^/])*\\*/|--[^\r\n]*[\r\n]|\\s)+"
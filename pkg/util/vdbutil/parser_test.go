@@ -252,3 +252,20 @@ func TestValidateDataSourceNames(t *testing.T) {
 	}
 	assert.NotNil(t, ValidateDataSourceNames(ds))
 }
+
+func TestSchemaNames(t *testing.T) {
+	var ddl = `CREATE DATABASE customer OPTIONS (ANNOTATION 'Customer VDB');
+	USE DATABASE customer;
+
+	CREATE SERVER sampledb TYPE "NONE" FOREIGN DATA WRAPPER postgresql;
+
+	CREATE SCHEMA accounts SERVER sampledb;
+	CREATE VIRTUAL SCHEMA "Portfolio";
+	IMPORT FOREIGN SCHEMA public FROM SERVER sampledb INTO accounts;`
+
+	schemas := ParseSchemaNamesFromDdl(ddl)
+
+	assert.Equal(t, 2, len(schemas))
+	assert.Equal(t, "accounts", schemas[0])
+	assert.Equal(t, "portfolio", schemas[1])
+}
@@ -3,7 +3,7 @@ IMAGE=$1
 
 sed "s|\$IMAGE_LOCATION|${IMAGE}|g" deploy/operator.yaml > deploy/operator-`whoami`.yaml
 
-for FILE in deploy/operator-`whoami`.yaml deploy/role_binding.yaml deploy/service_account.yaml deploy/role.yaml  deploy/crds/teiid.io_virtualdatabases_crd.yaml
+for FILE in deploy/operator-`whoami`.yaml deploy/role_binding.yaml deploy/service_account.yaml deploy/role.yaml  deploy/crds/teiid.io_virtualdatabases_crd.yaml deploy/crds/teiid.io_connectionfactories_crd.yaml
 do
 	oc delete -f ${FILE}
 done